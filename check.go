@@ -0,0 +1,219 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/protogodev/protogo/parser"
+	"github.com/protogodev/protogo/parser/ifacetool"
+)
+
+// Command is the `validate` command, which generates the validation
+// middleware, or only checks the annotations if Check is set.
+type Command struct {
+	Generator
+
+	Check          bool `name:"check" help:"check the annotations and report all errors without generating files"`
+	ListValidators bool `name:"list-validators" help:"print the resolved table of the builtin and custom validators, and exit"`
+
+	SrcFilename   string `arg:"" optional:"" name:"source-file" help:"source file"`
+	InterfaceName string `arg:"" optional:"" name:"interface-name" help:"interface name"`
+}
+
+func (c *Command) Run() error {
+	if c.ListValidators {
+		return c.Generator.ListValidators(os.Stdout)
+	}
+	if c.SrcFilename == "" || c.InterfaceName == "" {
+		return errors.New("expected <source-file> <interface-name>")
+	}
+
+	srcFilename, err := filepath.Abs(c.SrcFilename)
+	if err != nil {
+		return err
+	}
+
+	data, err := parser.ParseInterface(c.PkgName(), srcFilename, c.InterfaceName)
+	if err != nil {
+		return err
+	}
+
+	if c.Check {
+		errs, warnings := c.Generator.Check(data, srcFilename)
+		printWarnings(warnings)
+		for _, err := range errs {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		if len(errs) > 0 {
+			return fmt.Errorf("found %d error(s)", len(errs))
+		}
+		return nil
+	}
+
+	c.Generator.SrcFilename = srcFilename
+	file, err := c.Generate(data)
+	if err != nil {
+		return err
+	}
+	if err := file.Write(); err != nil {
+		return err
+	}
+
+	if c.Tests || c.Fuzz {
+		testFile, err := c.GenerateTests(data)
+		if err != nil {
+			return err
+		}
+		if err := testFile.Write(); err != nil {
+			return err
+		}
+	}
+
+	if c.Examples {
+		examplesFile, err := c.GenerateExamples(data)
+		if err != nil {
+			return err
+		}
+		return examplesFile.Write()
+	}
+	return nil
+}
+
+// Check parses and binds all the annotations of the interface, which is
+// declared in srcFilename, and returns all the errors found. The errors
+// are prefixed with the positions of the corresponding annotations. If
+// WarnUnknown is set, the errors of unknown keys are returned as warnings.
+func (g *Generator) Check(data *ifacetool.Data, srcFilename string) (errs, warnings []error) {
+	fset := token.NewFileSet()
+	file, err := goparser.ParseFile(fset, srcFilename, nil, goparser.ParseComments)
+	if err != nil {
+		return []error{err}, nil
+	}
+	return g.check(data, fset, file)
+}
+
+func (g *Generator) check(data *ifacetool.Data, fset *token.FileSet, file *ast.File) (errs, warnings []error) {
+	completeDecls, err := buildCompleteDecls(g.Custom, sourceImports(data, file))
+	if err != nil {
+		return []error{err}, nil
+	}
+	warnings = shadowWarnings(completeDecls)
+
+	filename := fset.Position(file.Package).Filename
+	builder := (&schemaBuilder{
+		decls:     completeDecls.validators,
+		qualifier: typeQualifier(data),
+	}).WithImports(sourceImports(data, file), filepath.Dir(filename))
+	docs := methodDocs(file, data.InterfaceName)
+
+	for _, method := range data.Methods {
+		annos := ParseDoc(method.Doc)
+		if doc, ok := docs[method.Name]; ok {
+			annos = ParseCommentGroup(doc)
+		}
+
+		_, err := buildMethodSchema(builder, method, annos)
+		var schemaErrs schemaErrors
+		if !errors.As(err, &schemaErrs) {
+			if err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		for _, e := range schemaErrs {
+			if e.Unknown && g.WarnUnknown {
+				warnings = append(warnings, positionError(fset, filename, e))
+				continue
+			}
+			errs = append(errs, positionError(fset, filename, e))
+		}
+	}
+
+	return errs, warnings
+}
+
+// shadowWarnings returns the warnings of the custom declarations, which
+// shadow the builtin ones without `override=true`.
+func shadowWarnings(decls *completeDecls) (warnings []error) {
+	for _, f := range decls.custom {
+		for _, d := range f.validators {
+			shadows := decls.shadows[d]
+			if len(shadows) == 0 || d.Override {
+				continue
+			}
+			var entries []string
+			for _, s := range shadows {
+				entries = append(entries, s.Entry())
+			}
+			warnings = append(warnings, fmt.Errorf("%s: validator `%s` (%s) shadows the builtin %s (add override=true if intended)",
+				f.filename, d.Alias, d.Entry(), strings.Join(entries, ", ")))
+		}
+	}
+	return warnings
+}
+
+// printWarnings prints warnings to stderr.
+func printWarnings(warnings []error) {
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", w)
+	}
+}
+
+// reExprPos matches the position prefix (e.g. `1:9 `) of the errors
+// reported by the expression parser.
+var reExprPos = regexp.MustCompile(`^1:(\d+):?\s+`)
+
+// positionError prefixes the schema error with the position of the option
+// value, which will be moved to the exact column if possible.
+func positionError(fset *token.FileSet, filename string, e *schemaError) error {
+	if !e.Opt.Pos.IsValid() {
+		// The annotation is not in the source file (e.g. the method comes
+		// from an embedded interface).
+		return fmt.Errorf("%s: %v", filename, e)
+	}
+
+	pos := fset.Position(e.Opt.Pos)
+	if m := reExprPos.FindStringSubmatch(e.Err.Error()); m != nil {
+		col, _ := strconv.Atoi(m[1])
+		pos.Column += col - 1
+		e = &schemaError{
+			Method: e.Method,
+			Anno:   e.Anno,
+			Opt:    e.Opt,
+			Err:    errors.New(e.Err.Error()[len(m[0]):]),
+		}
+	}
+	return fmt.Errorf("%s: %v", pos, e)
+}
+
+// methodDocs returns the doc comments of the methods declared in the
+// interface name.
+func methodDocs(file *ast.File, name string) map[string]*ast.CommentGroup {
+	docs := make(map[string]*ast.CommentGroup)
+	ast.Inspect(file, func(n ast.Node) bool {
+		ts, ok := n.(*ast.TypeSpec)
+		if !ok || ts.Name.Name != name {
+			return true
+		}
+		iface, ok := ts.Type.(*ast.InterfaceType)
+		if !ok {
+			return false
+		}
+		for _, m := range iface.Methods.List {
+			for _, n := range m.Names {
+				docs[n.Name] = m.Doc
+			}
+		}
+		return false
+	})
+	return docs
+}
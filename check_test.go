@@ -0,0 +1,137 @@
+package validate
+
+import (
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/protogodev/protogo/parser/ifacetool"
+)
+
+func TestGenerator_check(t *testing.T) {
+	src := "package user\n" +
+		"\n" +
+		"type Service interface {\n" +
+		"	// @schema:\n" +
+		"	//   name: len(1, 10) && nonzro\n" +
+		"	//   age: gte(0) && each(1)\n" +
+		"	//   user.Nme: nonzero\n" +
+		"	//   nme: len(1, 10)\n" +
+		"	//   ctx: nonzero\n" +
+		"	Update(ctx context.Context, name string, age int, user User) error\n" +
+		"\n" +
+		"	// @returns:\n" +
+		"	//   n: gt(0)\n" +
+		"	Count() (n int)\n" +
+		"\n" +
+		"	// @schema:\n" +
+		"	//   id: gt(0) == 3\n" +
+		"	Delete(id int) error\n" +
+		"}\n"
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "user.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+
+	pkg := types.NewPackage("example.com/user", "user")
+	user := types.NewNamed(types.NewTypeName(0, pkg, "User", nil), types.NewStruct([]*types.Var{
+		types.NewField(0, pkg, "Name", types.Typ[types.String], false),
+	}, nil), nil)
+
+	data := &ifacetool.Data{
+		SrcPkgName:    "user",
+		InterfaceName: "Service",
+		Methods: []*ifacetool.Method{
+			{
+				Name: "Update",
+				Params: []*ifacetool.Param{
+					{Name: "ctx", TypeString: "context.Context"},
+					{Name: "name", Type: types.Typ[types.String]},
+					{Name: "age", Type: types.Typ[types.Int]},
+					{Name: "user", Type: user},
+				},
+			},
+			{
+				Name: "Count",
+				Returns: []*ifacetool.Param{
+					{Name: "n", TypeString: "int", Type: types.Typ[types.Int]},
+				},
+			},
+			{
+				Name: "Delete",
+				Params: []*ifacetool.Param{
+					{Name: "id", Type: types.Typ[types.Int]},
+				},
+				Returns: []*ifacetool.Param{
+					{Name: "err", TypeString: "error", Type: types.Universe.Lookup("error").Type()},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		inGenerator  *Generator
+		wantErrs     []string
+		wantWarnings []string
+	}{
+		{
+			name:        "error",
+			inGenerator: &Generator{},
+			wantErrs: []string{
+				"user.go:5:13: method Update: @schema: name: unrecognized validator \"nonzro\"",
+				"user.go:6:27: method Update: @schema: age: unexpected 1",
+				"user.go:7:17: method Update: @schema: user.Nme: type User has no field Nme",
+				"user.go:8:12: method Update: @schema: nme: unknown name \"nme\" (did you mean \"name\"?)",
+				"user.go:9:12: method Update: @schema: ctx: cannot validate the context.Context param ctx",
+				"user.go:13:10: method Count: @returns: the last result must be an error",
+				"user.go:17:17: method Delete: @schema: id: expected && or ||, found ==",
+			},
+		},
+		{
+			name:        "warn unknown",
+			inGenerator: &Generator{WarnUnknown: true},
+			wantErrs: []string{
+				"user.go:5:13: method Update: @schema: name: unrecognized validator \"nonzro\"",
+				"user.go:6:27: method Update: @schema: age: unexpected 1",
+				"user.go:7:17: method Update: @schema: user.Nme: type User has no field Nme",
+				"user.go:9:12: method Update: @schema: ctx: cannot validate the context.Context param ctx",
+				"user.go:13:10: method Count: @returns: the last result must be an error",
+				"user.go:17:17: method Delete: @schema: id: expected && or ||, found ==",
+			},
+			wantWarnings: []string{
+				"user.go:8:12: method Update: @schema: nme: unknown name \"nme\" (did you mean \"name\"?)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs, warnings := tt.inGenerator.check(data, fset, file)
+
+			got := struct{ Errs, Warnings []string }{
+				Errs:     errStrings(errs),
+				Warnings: errStrings(warnings),
+			}
+			want := struct{ Errs, Warnings []string }{
+				Errs:     tt.wantErrs,
+				Warnings: tt.wantWarnings,
+			}
+			if !cmp.Equal(got, want) {
+				diff := cmp.Diff(got, want)
+				t.Errorf("Want - Got: %s", diff)
+			}
+		})
+	}
+}
+
+func errStrings(errs []error) (strs []string) {
+	for _, err := range errs {
+		strs = append(strs, err.Error())
+	}
+	return
+}
@@ -1,54 +1,63 @@
 package decl
 
 import (
+	"math"
+
 	v "github.com/RussellLuo/validating/v3"
 	"github.com/RussellLuo/vext"
+	"github.com/protogodev/validate/vtime"
 )
 
 var _ = []any{
+	// name=nonzero type=time args=0
+	vtime.Nonzero,
+
+	// name=zero type=time args=0
+	vtime.Zero,
+
 	// type=comparable args=0
 	v.Nonzero[string],
 
 	// type=comparable args=0
 	v.Zero[string],
 
-	// name=len type=string args=2
+	// name=len type=string args=min:int=0,max:int=math.MaxInt
 	v.LenString,
 
-	// name=len type=slice args=2
+	// name=len type=slice args=min:int=0,max:int=math.MaxInt
 	v.LenSlice[[]string],
 
-	// name=runecnt type=string|bytes args=2
+	// name=runecnt type=string|bytes args=min:int=0,max:int=math.MaxInt
 	v.RuneCount,
 
-	// type=comparable args=1
+	// type=comparable args=value:T
 	v.Eq[string],
 
-	// type=comparable args=1
+	// type=comparable args=value:T
 	v.Ne[string],
 
-	// type=ordered args=1
+	// type=ordered args=value:T
 	v.Gt[string],
 
-	// type=ordered args=1
+	// type=ordered args=value:T
 	v.Gte[string],
 
-	// type=ordered args=1
+	// type=ordered args=value:T
 	v.Lt[string],
 
-	// type=ordered args=1
+	// type=ordered args=value:T
 	v.Lte[string],
 
-	// name=xrange type=ordered args=2
+	// name=xrange type=ordered args=min:T,max:T
 	v.Range[string],
 
-	// type=ordered args=1+
+	// type=comparable args=T,T...
 	v.In[string],
 
-	// type=ordered args=1+
+	// type=comparable args=T,T...
 	v.Nin[string],
 
-	// type=string|bytes args=1
+	// type=string|bytes args=pattern:string
 	v.Match,
 
 	// type=string args=0
@@ -57,6 +66,24 @@ var _ = []any{
 	// type=string args=0
 	vext.IP,
 
-	// type=string args=1
+	// type=string args=layout:string
 	vext.Time,
+
+	// type=time args=t:time
+	vtime.After,
+
+	// type=time args=t:time
+	vtime.Before,
+
+	// type=time args=d:duration
+	vtime.Within,
+
+	// type=time args=0
+	vtime.Future,
+
+	// type=time args=0
+	vtime.Past,
 }
+
+// Referenced by the default values of the arguments above.
+var _ = math.MaxInt
@@ -0,0 +1,36 @@
+package decl
+
+import (
+	"fmt"
+	"go/types"
+)
+
+// CheckCtx checks whether the constructor of the context-aware validator v,
+// which is declared in pkg, accepts a context.Context as the first argument.
+func CheckCtx(v *Validator, pkg *types.Package) error {
+	obj := pkg.Scope().Lookup(v.Name)
+	if obj == nil {
+		return fmt.Errorf("validator `%s`: %s.%s not found", v.Alias, v.Qualifier, v.Name)
+	}
+
+	sig, ok := obj.Type().(*types.Signature)
+	if !ok {
+		return fmt.Errorf("validator `%s`: %s.%s is not a function", v.Alias, v.Qualifier, v.Name)
+	}
+
+	params := sig.Params()
+	if params.Len() == 0 || !isContext(params.At(0).Type()) {
+		return fmt.Errorf("validator `%s`: %s.%s must accept a context.Context as the first argument", v.Alias, v.Qualifier, v.Name)
+	}
+
+	return nil
+}
+
+func isContext(typ types.Type) bool {
+	named, ok := typ.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "context" && obj.Name() == "Context"
+}
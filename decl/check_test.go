@@ -0,0 +1,76 @@
+package decl_test
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/protogodev/validate/decl"
+)
+
+func TestCheckCtx(t *testing.T) {
+	src := `package tenant
+
+import "context"
+
+func Tenant(ctx context.Context) bool { return true }
+
+func NoCtx(s string) bool { return true }
+
+var NotFunc = 1
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "tenant.go", src, 0)
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check("example.com/tenant", fset, []*ast.File{file}, nil)
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+
+	tests := []struct {
+		name       string
+		inName     string
+		wantErrStr string
+	}{
+		{
+			name:   "ok",
+			inName: "Tenant",
+		},
+		{
+			name:       "no ctx",
+			inName:     "NoCtx",
+			wantErrStr: "validator `x`: tenant.NoCtx must accept a context.Context as the first argument",
+		},
+		{
+			name:       "not func",
+			inName:     "NotFunc",
+			wantErrStr: "validator `x`: tenant.NotFunc is not a function",
+		},
+		{
+			name:       "not found",
+			inName:     "Unknown",
+			wantErrStr: "validator `x`: tenant.Unknown not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &decl.Validator{Qualifier: "tenant", Name: tt.inName, Alias: "x", Ctx: true}
+			err := decl.CheckCtx(v, pkg)
+
+			var gotErrStr string
+			if err != nil {
+				gotErrStr = err.Error()
+			}
+			if gotErrStr != tt.wantErrStr {
+				t.Errorf("Err: got (%#v), want (%#v)", gotErrStr, tt.wantErrStr)
+			}
+		})
+	}
+}
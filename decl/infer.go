@@ -0,0 +1,280 @@
+package decl
+
+import (
+	"errors"
+	"fmt"
+	"go/types"
+)
+
+// ErrInexactTypes is returned by Infer if the allowed types cannot be derived
+// exactly from the signature of the constructor, in which case they must be
+// specified explicitly by the key "type" in the comment.
+var ErrInexactTypes = errors.New("cannot infer the allowed types exactly")
+
+// Infer infers the metadata of the validator v from the signature of its
+// constructor, which is declared in pkg:
+//
+//   - Ctx is set if the first parameter is a context.Context.
+//   - IsGeneric is set if the constructor has type parameters, and
+//     AllowedTypes is derived from the constraint of the first one (e.g.
+//     "string" for `~string`, and "integer" for `constraints.Integer`).
+//   - Args is derived from the remaining parameters.
+//
+// If the constructor is not generic, or the constraint is not made up of
+// whole type classes (e.g. `~int | ~int64`, which is narrower than
+// "integer"), AllowedTypes is left nil and an error wrapping
+// ErrInexactTypes is returned after the other metadata has been inferred.
+func Infer(v *Validator, pkg *types.Package) error {
+	obj := pkg.Scope().Lookup(v.Name)
+	if obj == nil {
+		return fmt.Errorf("%s.%s not found", v.Qualifier, v.Name)
+	}
+
+	sig, ok := obj.Type().(*types.Signature)
+	if !ok {
+		return fmt.Errorf("%s.%s is not a function", v.Qualifier, v.Name)
+	}
+
+	params := sig.Params()
+	start := 0
+	if params.Len() > 0 && isContext(params.At(0).Type()) {
+		v.Ctx = true
+		start = 1
+	}
+
+	var tparam *types.TypeParam
+	var typesErr error
+	if sig.TypeParams().Len() > 0 {
+		tparam = sig.TypeParams().At(0)
+		v.IsGeneric = true
+		v.AllowedTypes, typesErr = constraintTypes(tparam.Constraint(), types.RelativeTo(pkg))
+	} else {
+		typesErr = fmt.Errorf("%w: %s.%s is not generic", ErrInexactTypes, v.Qualifier, v.Name)
+	}
+
+	args := make([]Arg, 0, params.Len()-start)
+	for i := start; i < params.Len(); i++ {
+		p := params.At(i)
+		arg := Arg{Name: p.Name()}
+		if arg.Name == "_" {
+			arg.Name = ""
+		}
+
+		typ := p.Type()
+		if sig.Variadic() && i == params.Len()-1 {
+			arg.Variadic = true
+			typ = typ.(*types.Slice).Elem()
+		}
+		arg.Kind = argKind(typ, tparam)
+
+		args = append(args, arg)
+	}
+	v.Args = args
+	v.ArgNum = argNum(args)
+
+	return typesErr
+}
+
+// argKind returns the argument kind of typ, which is "any" if typ cannot be
+// represented by the other kinds.
+func argKind(typ types.Type, tparam *types.TypeParam) string {
+	if tparam != nil && types.Identical(typ, tparam) {
+		return "T"
+	}
+
+	switch {
+	case IsTime(typ):
+		return "time"
+	case IsDuration(typ):
+		return "duration"
+	}
+
+	if b, ok := typ.(*types.Basic); ok {
+		switch b.Kind() {
+		case types.Int:
+			return "int"
+		case types.Uint:
+			return "uint"
+		case types.Float64:
+			return "float"
+		case types.String:
+			return "string"
+		case types.Bool:
+			return "bool"
+		}
+	}
+	return "any"
+}
+
+// integerKinds, floatKinds and complexKinds are the kinds of the basic types
+// in the type classes "integer", "float" and the complex part of "numeric".
+var (
+	integerKinds = []types.BasicKind{
+		types.Int, types.Int8, types.Int16, types.Int32, types.Int64,
+		types.Uint, types.Uint8, types.Uint16, types.Uint32, types.Uint64, types.Uintptr,
+	}
+	floatKinds   = []types.BasicKind{types.Float32, types.Float64}
+	complexKinds = []types.BasicKind{types.Complex64, types.Complex128}
+)
+
+// constraintTypes returns the allowed types of the type constraint c, which
+// must allow exactly the types in the type set of c. Otherwise, an error
+// wrapping ErrInexactTypes is returned.
+func constraintTypes(c types.Type, qf types.Qualifier) (Types, error) {
+	inexact := func(reason string) error {
+		return fmt.Errorf("%w: constraint %s %s", ErrInexactTypes, types.TypeString(c, qf), reason)
+	}
+
+	iface, ok := c.Underlying().(*types.Interface)
+	if !ok || iface.NumMethods() > 0 {
+		// The methods cannot be represented by the allowed types.
+		return nil, inexact("has methods")
+	}
+
+	terms, ok := unionTerms(iface)
+	if !ok {
+		return nil, inexact("is not a union of types")
+	}
+	if len(terms) == 0 {
+		if iface.IsComparable() {
+			return Types{"comparable"}, nil
+		}
+		return Types{"any"}, nil
+	}
+
+	kinds := make(map[types.BasicKind]bool)
+	var bytes bool
+	for _, t := range terms {
+		if !t.Tilde() {
+			// The named types with the same underlying type are not allowed.
+			return nil, inexact(fmt.Sprintf("has term %s without ~", types.TypeString(t.Type(), qf)))
+		}
+		switch u := t.Type().(type) {
+		case *types.Basic:
+			kinds[u.Kind()] = true
+			continue
+		case *types.Slice:
+			if b, ok := u.Elem().(*types.Basic); ok && b.Kind() == types.Byte {
+				bytes = true
+				continue
+			}
+		}
+		// The composite types other than []byte (e.g. ~[]int) are narrower
+		// than their type classes (e.g. "slice").
+		return nil, inexact(fmt.Sprintf("has term %s, which is not a type class", t))
+	}
+
+	// has reports whether all of the kinds are in the type set, and removes
+	// them from kinds. It fails if only part of the kinds are there.
+	var partial bool
+	has := func(ks ...types.BasicKind) bool {
+		n := 0
+		for _, k := range ks {
+			if kinds[k] {
+				delete(kinds, k)
+				n++
+			}
+		}
+		partial = partial || (n > 0 && n < len(ks))
+		return n == len(ks)
+	}
+	integer, float, complex, str := has(integerKinds...), has(floatKinds...), has(complexKinds...), has(types.String)
+	if partial || len(kinds) > 0 || (complex && !(integer && float)) {
+		return nil, inexact("is not made up of whole type classes")
+	}
+
+	var ts Types
+	switch {
+	case complex:
+		ts = append(ts, "numeric")
+		if str {
+			ts = append(ts, "string")
+		}
+	case integer && float && str:
+		ts = append(ts, "ordered")
+	case integer && float:
+		ts = append(ts, "number")
+	default:
+		for _, t := range []struct {
+			ok   bool
+			name string
+		}{
+			{integer, "integer"},
+			{float, "float"},
+			{str, "string"},
+		} {
+			if t.ok {
+				ts = append(ts, t.name)
+			}
+		}
+	}
+	if bytes {
+		ts = append(ts, "bytes")
+	}
+	return ts, nil
+}
+
+// unionTerms returns the terms of the union, which makes up the type set of
+// iface, including those from the embedded constraints (e.g.
+// `constraints.Integer`). It fails if the type set is not a union of types,
+// e.g. if iface embeds more than one element (i.e. an intersection), or the
+// union has an unrestricted term (e.g. `any` or `comparable`).
+func unionTerms(iface *types.Interface) ([]*types.Term, bool) {
+	switch iface.NumEmbeddeds() {
+	case 0:
+		return nil, true
+	case 1:
+	default:
+		return nil, false
+	}
+
+	var terms []*types.Term
+	var add func(t *types.Term) bool
+	add = func(t *types.Term) bool {
+		if u, ok := t.Type().(*types.Union); ok {
+			for i := 0; i < u.Len(); i++ {
+				if !add(u.Term(i)) {
+					return false
+				}
+			}
+			return true
+		}
+		if inner, ok := t.Type().Underlying().(*types.Interface); ok {
+			ts, ok := unionTerms(inner)
+			if !ok || len(ts) == 0 {
+				return false
+			}
+			terms = append(terms, ts...)
+			return true
+		}
+		terms = append(terms, t)
+		return true
+	}
+	if !add(types.NewTerm(false, iface.EmbeddedType(0))) {
+		return nil, false
+	}
+	return terms, true
+}
+
+// collectTerms collects the types of the terms in the type set of iface,
+// including those from the embedded constraints (e.g. `constraints.Integer`).
+func collectTerms(iface *types.Interface, terms *[]types.Type) {
+	for i := 0; i < iface.NumEmbeddeds(); i++ {
+		collectTerm(iface.EmbeddedType(i), terms)
+	}
+}
+
+func collectTerm(typ types.Type, terms *[]types.Type) {
+	switch t := typ.(type) {
+	case *types.Union:
+		for i := 0; i < t.Len(); i++ {
+			collectTerm(t.Term(i).Type(), terms)
+		}
+	default:
+		if iface, ok := typ.Underlying().(*types.Interface); ok {
+			collectTerms(iface, terms)
+			return
+		}
+		*terms = append(*terms, typ)
+	}
+}
@@ -0,0 +1,360 @@
+package decl_test
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/protogodev/validate/decl"
+)
+
+func checkPackage(t *testing.T, path, src string) *types.Package {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "c.go", src, 0)
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check(path, fset, []*ast.File{file}, nil)
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+	return pkg
+}
+
+const customSrc = `package c
+
+import "context"
+
+type Signed interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64
+}
+
+type Integer interface {
+	Signed | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+type Float interface{ ~float32 | ~float64 }
+
+type Ordered interface {
+	Integer | Float | ~string
+}
+
+func UUID() bool { return true }
+
+func Tenant[T ~string](ctx context.Context, prefix string) bool { return true }
+
+func Between[T Ordered](min, max T) bool { return true }
+
+func Positive[T Integer]() bool { return true }
+
+func Ratio[T Float]() bool { return true }
+
+func Sign[T Integer | Float]() bool { return true }
+
+func Abs[T Integer | Float | ~complex64 | ~complex128]() bool { return true }
+
+func Code[T Integer | ~string]() bool { return true }
+
+func OneOf[T comparable](values ...T) bool { return true }
+
+func Len[S ~string | ~[]byte](min int, _ int) bool { return true }
+
+func Misc[T any](x float64, y bool, z uint, p *int) bool { return true }
+
+func Small[T Signed]() bool { return true }
+
+func Wide[T ~int | ~string]() bool { return true }
+
+func Exact[T int]() bool { return true }
+
+func Complex[T ~complex64 | ~complex128]() bool { return true }
+
+func Keys[M ~map[string]int]() bool { return true }
+
+func Stringer[T interface{ String() string }]() bool { return true }
+
+func Both[T interface{ Integer; ~int }]() bool { return true }
+
+var NotFunc = 1
+`
+
+func TestInfer(t *testing.T) {
+	pkg := checkPackage(t, "example.com/c", customSrc)
+
+	tests := []struct {
+		name       string
+		inName     string
+		want       *decl.Validator
+		wantErrStr string
+	}{
+		{
+			name:   "ctx",
+			inName: "Tenant",
+			want: &decl.Validator{
+				Name:         "Tenant",
+				IsGeneric:    true,
+				AllowedTypes: []string{"string"},
+				ArgNum:       decl.Range{Min: 1, Max: 1},
+				Args:         []decl.Arg{{Name: "prefix", Kind: "string"}},
+				Ctx:          true,
+			},
+		},
+		{
+			name:   "ordered",
+			inName: "Between",
+			want: &decl.Validator{
+				Name:         "Between",
+				IsGeneric:    true,
+				AllowedTypes: []string{"ordered"},
+				ArgNum:       decl.Range{Min: 2, Max: 2},
+				Args:         []decl.Arg{{Name: "min", Kind: "T"}, {Name: "max", Kind: "T"}},
+			},
+		},
+		{
+			name:   "integer",
+			inName: "Positive",
+			want: &decl.Validator{
+				Name:         "Positive",
+				IsGeneric:    true,
+				AllowedTypes: []string{"integer"},
+				Args:         []decl.Arg{},
+			},
+		},
+		{
+			name:   "float",
+			inName: "Ratio",
+			want: &decl.Validator{
+				Name:         "Ratio",
+				IsGeneric:    true,
+				AllowedTypes: []string{"float"},
+				Args:         []decl.Arg{},
+			},
+		},
+		{
+			name:   "number",
+			inName: "Sign",
+			want: &decl.Validator{
+				Name:         "Sign",
+				IsGeneric:    true,
+				AllowedTypes: []string{"number"},
+				Args:         []decl.Arg{},
+			},
+		},
+		{
+			name:   "numeric",
+			inName: "Abs",
+			want: &decl.Validator{
+				Name:         "Abs",
+				IsGeneric:    true,
+				AllowedTypes: []string{"numeric"},
+				Args:         []decl.Arg{},
+			},
+		},
+		{
+			name:   "integer or string",
+			inName: "Code",
+			want: &decl.Validator{
+				Name:         "Code",
+				IsGeneric:    true,
+				AllowedTypes: []string{"integer", "string"},
+				Args:         []decl.Arg{},
+			},
+		},
+		{
+			name:   "comparable variadic",
+			inName: "OneOf",
+			want: &decl.Validator{
+				Name:         "OneOf",
+				IsGeneric:    true,
+				AllowedTypes: []string{"comparable"},
+				ArgNum:       decl.Range{Min: 0, Max: math.MaxInt},
+				Args:         []decl.Arg{{Name: "values", Kind: "T", Variadic: true}},
+			},
+		},
+		{
+			name:   "union",
+			inName: "Len",
+			want: &decl.Validator{
+				Name:         "Len",
+				IsGeneric:    true,
+				AllowedTypes: []string{"string", "bytes"},
+				ArgNum:       decl.Range{Min: 2, Max: 2},
+				Args:         []decl.Arg{{Name: "min", Kind: "int"}, {Kind: "int"}},
+			},
+		},
+		{
+			name:   "arg kinds",
+			inName: "Misc",
+			want: &decl.Validator{
+				Name:         "Misc",
+				IsGeneric:    true,
+				AllowedTypes: []string{"any"},
+				ArgNum:       decl.Range{Min: 4, Max: 4},
+				Args: []decl.Arg{
+					{Name: "x", Kind: "float"},
+					{Name: "y", Kind: "bool"},
+					{Name: "z", Kind: "uint"},
+					{Name: "p", Kind: "any"},
+				},
+			},
+		},
+		{
+			name:       "not generic",
+			inName:     "UUID",
+			wantErrStr: "cannot infer the allowed types exactly: c.UUID is not generic",
+		},
+		{
+			name:       "part of a type class",
+			inName:     "Small",
+			wantErrStr: "cannot infer the allowed types exactly: constraint Signed is not made up of whole type classes",
+		},
+		{
+			name:       "widened union",
+			inName:     "Wide",
+			wantErrStr: "cannot infer the allowed types exactly: constraint ~int | ~string is not made up of whole type classes",
+		},
+		{
+			name:       "term without tilde",
+			inName:     "Exact",
+			wantErrStr: "cannot infer the allowed types exactly: constraint int has term int without ~",
+		},
+		{
+			name:       "complex only",
+			inName:     "Complex",
+			wantErrStr: "cannot infer the allowed types exactly: constraint ~complex64 | ~complex128 is not made up of whole type classes",
+		},
+		{
+			name:       "composite term",
+			inName:     "Keys",
+			wantErrStr: "cannot infer the allowed types exactly: constraint ~map[string]int has term ~map[string]int, which is not a type class",
+		},
+		{
+			name:       "methods",
+			inName:     "Stringer",
+			wantErrStr: "cannot infer the allowed types exactly: constraint interface{String() string} has methods",
+		},
+		{
+			name:       "intersection",
+			inName:     "Both",
+			wantErrStr: "cannot infer the allowed types exactly: constraint interface{Integer; ~int} is not a union of types",
+		},
+		{
+			name:       "not func",
+			inName:     "NotFunc",
+			wantErrStr: "c.NotFunc is not a function",
+		},
+		{
+			name:       "not found",
+			inName:     "Unknown",
+			wantErrStr: "c.Unknown not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &decl.Validator{Qualifier: "c", Name: tt.inName}
+			err := decl.Infer(got, pkg)
+
+			var gotErrStr string
+			if err != nil {
+				gotErrStr = err.Error()
+			}
+			if gotErrStr != tt.wantErrStr {
+				t.Fatalf("Err: got (%#v), want (%#v)", gotErrStr, tt.wantErrStr)
+			}
+			if err != nil {
+				return
+			}
+
+			tt.want.Qualifier = "c"
+			if !cmp.Equal(got, tt.want) {
+				diff := cmp.Diff(got, tt.want)
+				t.Errorf("Want - Got: %s", diff)
+			}
+		})
+	}
+}
+
+func TestParseFile_Types(t *testing.T) {
+	pkg := checkPackage(t, "example.com/c", customSrc)
+
+	src := `package custom
+
+import (
+	"example.com/c"
+)
+
+var _ = []any{
+	c.Between[int],
+
+	// name=uuid type=string
+	c.UUID,
+
+	// args=1
+	c.Tenant,
+}
+`
+	got, err := decl.ParseFile("custom.go", src, map[string]*types.Package{"example.com/c": pkg})
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+
+	want := []*decl.Validator{
+		{
+			Import:       "example.com/c",
+			Qualifier:    "c",
+			Name:         "Between",
+			IsGeneric:    true,
+			Alias:        "between",
+			AllowedTypes: []string{"ordered"},
+			ArgNum:       decl.Range{Min: 2, Max: 2},
+			Args:         []decl.Arg{{Name: "min", Kind: "T"}, {Name: "max", Kind: "T"}},
+		},
+		{
+			Import:       "example.com/c",
+			Qualifier:    "c",
+			Name:         "UUID",
+			Alias:        "uuid",
+			AllowedTypes: []string{"string"},
+			Args:         []decl.Arg{},
+		},
+		{
+			Import:       "example.com/c",
+			Qualifier:    "c",
+			Name:         "Tenant",
+			IsGeneric:    true,
+			Alias:        "tenant",
+			AllowedTypes: []string{"string"},
+			ArgNum:       decl.Range{Min: 1, Max: 1},
+			Ctx:          true,
+		},
+	}
+	if !cmp.Equal(got, want) {
+		diff := cmp.Diff(got, want)
+		t.Errorf("Want - Got: %s", diff)
+	}
+
+	// Without the types, the comments are required.
+	wantErrStr := "8: c.Between: missing comment (e.g. `// type=string args=0`)"
+	if _, err := decl.Parse(src); err == nil || err.Error() != wantErrStr {
+		t.Errorf("Err: got (%v), want (%#v)", err, wantErrStr)
+	}
+
+	// The allowed types, which cannot be inferred exactly, are required.
+	for _, comment := range []string{"", "// name=small\n"} {
+		src := "package custom\n\nimport \"example.com/c\"\n\nvar _ = []any{\n" + comment + "c.Small,\n}\n"
+		wantErrStr := fmt.Sprintf("custom.go:%d: c.Small: cannot infer the allowed types exactly: constraint Signed is not made up of whole type classes (want an explicit `type=...` in the comment)", 6+strings.Count(comment, "\n"))
+		_, err := decl.ParseFile("custom.go", src, map[string]*types.Package{"example.com/c": pkg})
+		if err == nil || err.Error() != wantErrStr {
+			t.Errorf("Err: got (%v), want (%#v)", err, wantErrStr)
+		}
+	}
+}
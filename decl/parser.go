@@ -3,6 +3,7 @@ package decl
 import (
 	_ "embed"
 	"errors"
+	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
@@ -21,8 +22,31 @@ var ErrBadDecl = errors.New("bad declaration of var `_`")
 
 var reVersion = regexp.MustCompile(`(/v[0-9]+)$`)
 
+// Types are the allowed types of a validator, each of which is either a type
+// class (see typeNames), or a named type qualified by its import path (e.g.
+// `time.Time` or `example.com/money.Money`).
 type Types []string
 
+// typeNames are the names of the type classes.
+var typeNames = map[string]bool{
+	"comparable": true,
+	"ordered":    true,
+	"number":     true,
+	"integer":    true,
+	"float":      true,
+	"numeric":    true,
+	"string":     true,
+	"bytes":      true,
+	"slice":      true,
+	"array":      true,
+	"map":        true,
+	"pointer":    true,
+	"struct":     true,
+	"time":       true,
+	"duration":   true,
+	"any":        true,
+}
+
 func (ts Types) Allow(typ types.Type) bool {
 	for _, t := range ts {
 		switch t {
@@ -34,6 +58,22 @@ func (ts Types) Allow(typ types.Type) bool {
 			if IsOrdered(typ) {
 				return true
 			}
+		case "number":
+			if IsNumber(typ) {
+				return true
+			}
+		case "integer":
+			if IsInteger(typ) {
+				return true
+			}
+		case "float":
+			if IsFloat(typ) {
+				return true
+			}
+		case "numeric":
+			if IsNumeric(typ) {
+				return true
+			}
 		case "string":
 			if IsString(typ) {
 				return true
@@ -46,11 +86,51 @@ func (ts Types) Allow(typ types.Type) bool {
 			if IsSlice(typ) {
 				return true
 			}
+		case "array":
+			if IsArray(typ) {
+				return true
+			}
+		case "map":
+			if IsMap(typ) {
+				return true
+			}
+		case "pointer":
+			if IsPointer(typ) {
+				return true
+			}
+		case "struct":
+			if IsStruct(typ) {
+				return true
+			}
+		case "time":
+			if IsTime(typ) {
+				return true
+			}
+		case "duration":
+			if IsDuration(typ) {
+				return true
+			}
+		case "any":
+			return true
+		default:
+			if path, name, ok := splitNamedType(t); ok && IsNamed(typ, path, name) {
+				return true
+			}
 		}
 	}
 	return false
 }
 
+// splitNamedType splits the named type t (e.g. `example.com/money.Money`)
+// into the import path and the type name.
+func splitNamedType(t string) (path, name string, ok bool) {
+	i := strings.LastIndex(t, ".")
+	if i == -1 {
+		return "", "", false
+	}
+	return t[:i], t[i+1:], true
+}
+
 type Range struct {
 	Min, Max int
 }
@@ -59,6 +139,36 @@ func (r Range) Contain(n int) bool {
 	return n >= r.Min && n <= r.Max
 }
 
+// Arg is a declared argument of a validator, e.g. `max:int=100`.
+type Arg struct {
+	// Name is the argument name, which can be used to pass the argument by
+	// name (e.g. `len(max: 10)`). It is empty for the unnamed arguments.
+	Name string
+	// Kind is the argument kind, which is one of "int", "uint", "float",
+	// "string", "bool", "time" (i.e. time.Time), "duration" (i.e.
+	// time.Duration), "T" (i.e. the type of the value being validated) and
+	// "any" (i.e. unchecked).
+	Kind string
+	// Default is the default value, which will be used if the argument
+	// is omitted.
+	Default string
+	// Variadic reports whether the argument is variadic (e.g. `T...`).
+	Variadic bool
+}
+
+// argKinds are the allowed kinds of the declared arguments.
+var argKinds = map[string]bool{
+	"int":      true,
+	"uint":     true,
+	"float":    true,
+	"string":   true,
+	"bool":     true,
+	"time":     true,
+	"duration": true,
+	"T":        true,
+	"any":      true,
+}
+
 type Validator struct {
 	Import       string
 	Qualifier    string
@@ -67,15 +177,78 @@ type Validator struct {
 	Alias        string
 	AllowedTypes Types
 	ArgNum       Range
+	// Args holds the declared arguments (e.g. `args=min:int,max:int`), if any.
+	// It is empty if only the number of arguments is declared (e.g. `args=2`).
+	Args []Arg
+	// DefaultImports maps the qualifiers, which are referenced by the default
+	// values of Args (e.g. `math` in `math.MaxInt`), to the import paths.
+	DefaultImports map[string]string
+	// Ctx reports whether the validator constructor accepts a context.Context
+	// as the first argument, which is not counted in ArgNum.
+	Ctx bool
+	// JSONSchema is the extension keyword (e.g. `x-uuid`), which represents
+	// the validator in JSON Schema.
+	JSONSchema string
+	// Example is a value satisfying the validator as a Go literal (e.g.
+	// `"acme:123"`), which is used to synthesize the example values.
+	Example string
+	// Override reports whether the validator is meant to override the
+	// builtin ones of the same alias (e.g. a custom `len`).
+	Override bool
+}
+
+// Entry returns the entry of the validator in the declarations, e.g.
+// `validating.Nonzero`.
+func (v *Validator) Entry() string {
+	return v.Qualifier + "." + v.Name
+}
+
+// Error is an error of the declarations, which is positioned at the
+// offending entry (or its comment).
+type Error struct {
+	Pos token.Position
+	// Entry is the offending entry (e.g. `custom.UUID`), if any.
+	Entry string
+	// Key is the offending key of the comment (e.g. `args`), if any.
+	Key string
+	Err error
+}
+
+func (e *Error) Error() string {
+	pos := e.Pos
+	pos.Column = 0 // Only report the line.
+
+	prefix := pos.String()
+	if e.Entry != "" {
+		prefix += ": " + e.Entry
+	}
+	if e.Key != "" {
+		prefix += ": " + e.Key
+	}
+	return fmt.Sprintf("%s: %v", prefix, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
 }
 
 func Parse(decls string) ([]*Validator, error) {
-	if decls == "" {
+	return ParseFile("", decls, nil)
+}
+
+// ParseFile parses the declarations src from filename, which is only used to
+// report the errors. If pkgs is not nil, ParseFile also infers the metadata
+// of each validator from the signature of its constructor (see Infer), which
+// is looked up in pkgs keyed by the import path. In this case, the comment
+// of each validator is optional, whose fields, if any, will override the
+// inferred ones.
+func ParseFile(filename, src string, pkgs map[string]*types.Package) ([]*Validator, error) {
+	if src == "" {
 		return nil, nil
 	}
 
 	fset := token.NewFileSet()
-	f, err := parser.ParseFile(fset, "", decls, parser.ParseComments)
+	f, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
 	if err != nil {
 		return nil, err
 	}
@@ -99,6 +272,7 @@ func Parse(decls string) ([]*Validator, error) {
 				fset:     fset,
 				imports:  parseImports(f.Imports),
 				comments: f.Comments,
+				pkgs:     pkgs,
 			}
 			return p.Parse(vs.Values)
 		}
@@ -131,6 +305,7 @@ type Parser struct {
 	fset     *token.FileSet
 	imports  map[string]string
 	comments []*ast.CommentGroup
+	pkgs     map[string]*types.Package
 }
 
 func (p Parser) Parse(values []ast.Expr) ([]*Validator, error) {
@@ -140,7 +315,7 @@ func (p Parser) Parse(values []ast.Expr) ([]*Validator, error) {
 
 	value, ok := values[0].(*ast.CompositeLit)
 	if !ok {
-		return nil, ErrBadDecl
+		return nil, p.error(values[0], "", "", fmt.Errorf("%w: want a composite literal (e.g. `[]any{...}`)", ErrBadDecl))
 	}
 
 	var validators []*Validator
@@ -149,7 +324,7 @@ func (p Parser) Parse(values []ast.Expr) ([]*Validator, error) {
 		case *ast.IndexExpr:
 			x, ok := e.X.(*ast.SelectorExpr)
 			if !ok {
-				return nil, ErrBadDecl
+				return nil, p.badEntryError(e)
 			}
 
 			validator, err := p.parseValidator(x)
@@ -169,7 +344,7 @@ func (p Parser) Parse(values []ast.Expr) ([]*Validator, error) {
 			validators = append(validators, validator)
 
 		default:
-			return nil, ErrBadDecl
+			return nil, p.badEntryError(e)
 		}
 	}
 
@@ -181,66 +356,270 @@ func (p Parser) parseValidator(e *ast.SelectorExpr) (*Validator, error) {
 	if err != nil {
 		return nil, err
 	}
-
-	comment := p.getComment(pos)
-	comment = strings.TrimPrefix(comment, "//")
-	if comment == "" {
-		return nil, ErrBadDecl
-	}
-
 	validator := &Validator{
 		Import:    p.imports[qualifier],
 		Qualifier: qualifier,
 		Name:      name,
 		Alias:     strings.ToLower(name), // Defaults to the lowercase version of name.
 	}
+	entry := validator.Entry()
+	if validator.Import == "" {
+		return nil, p.error(e, entry, "", fmt.Errorf("package %s is not imported", qualifier))
+	}
+
+	// typesErr reports that the allowed types cannot be inferred exactly,
+	// which therefore must be specified in the comment.
+	var typesErr error
+	pkg, inferable := p.pkgs[validator.Import]
+	if inferable {
+		if err := Infer(validator, pkg); err != nil {
+			if !errors.Is(err, ErrInexactTypes) {
+				return nil, p.error(e, entry, "", err)
+			}
+			typesErr = fmt.Errorf("%w (want an explicit `type=...` in the comment)", err)
+		}
+	}
+
+	c := p.getComment(pos)
+	if c == nil {
+		if typesErr != nil {
+			return nil, p.error(e, entry, "", typesErr)
+		}
+		if inferable {
+			return validator, nil
+		}
+		return nil, p.error(e, entry, "", errors.New("missing comment (e.g. `// type=string args=0`)"))
+	}
+	comment := strings.TrimPrefix(c.Text, "//")
 
 	fields := strings.Fields(comment)
 	for _, f := range fields {
-		parts := strings.Split(f, "=")
+		parts := strings.SplitN(f, "=", 2)
+		if len(parts) != 2 {
+			return nil, p.error(c, entry, "", fmt.Errorf("bad field %q (want key=value)", f))
+		}
 		k, v := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
 		switch k {
 		case "name":
+			if !token.IsIdentifier(v) {
+				return nil, p.error(c, entry, k, fmt.Errorf("bad name %q", v))
+			}
 			validator.Alias = v
 		case "type":
-			validator.AllowedTypes = strings.Split(v, "|")
+			ts := strings.Split(v, "|")
+			for i, t := range ts {
+				if typeNames[t] {
+					continue
+				}
+				named, err := p.parseNamedType(t)
+				if err != nil {
+					return nil, p.error(c, entry, k, err)
+				}
+				ts[i] = named
+			}
+			validator.AllowedTypes = ts
 		case "args":
-			if strings.HasSuffix(v, "+") {
-				v = strings.TrimSuffix(v, "+")
-				n := mustAtoi(v)
-				validator.ArgNum = Range{Min: n, Max: math.MaxInt}
-			} else {
-				n := mustAtoi(v)
+			plus := strings.HasSuffix(v, "+")
+			if n, err := strconv.Atoi(strings.TrimSuffix(v, "+")); err == nil {
 				validator.ArgNum = Range{Min: n, Max: n}
+				if plus {
+					validator.ArgNum.Max = math.MaxInt
+				}
+				validator.Args, validator.DefaultImports = nil, nil
+				continue
 			}
+
+			args, err := parseArgs(v)
+			if err != nil {
+				return nil, p.error(c, entry, k, err)
+			}
+			validator.Args = args
+			validator.ArgNum = argNum(args)
+			validator.DefaultImports = p.defaultImports(args)
+		case "ctx":
+			ctx, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, p.error(c, entry, k, fmt.Errorf("bad value %q (want true or false)", v))
+			}
+			validator.Ctx = ctx
+		case "jsonschema":
+			validator.JSONSchema = v
+		case "example":
+			if !isLiteral(v) {
+				return nil, p.error(c, entry, k, fmt.Errorf("bad value %q (want a Go literal, e.g. \"abc\" or 10)", v))
+			}
+			validator.Example = v
+		case "override":
+			override, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, p.error(c, entry, k, fmt.Errorf("bad value %q (want true or false)", v))
+			}
+			validator.Override = override
+		default:
+			return nil, p.error(c, entry, "", fmt.Errorf("unknown key %q", k))
+		}
+	}
+
+	if validator.AllowedTypes == nil {
+		if typesErr != nil {
+			return nil, p.error(e, entry, "", typesErr)
 		}
+		return nil, p.error(c, entry, "", errors.New("missing key \"type\""))
 	}
 
 	return validator, nil
 }
 
+// isLiteral reports whether s is a Go literal, optionally negated.
+func isLiteral(s string) bool {
+	e, err := parser.ParseExpr(s)
+	if err != nil {
+		return false
+	}
+	if u, ok := e.(*ast.UnaryExpr); ok && u.Op == token.SUB {
+		e = u.X
+	}
+	_, ok := e.(*ast.BasicLit)
+	return ok
+}
+
+// error returns an error positioned at node.
+func (p Parser) error(node ast.Node, entry, key string, err error) error {
+	return &Error{
+		Pos:   p.fset.Position(node.Pos()),
+		Entry: entry,
+		Key:   key,
+		Err:   err,
+	}
+}
+
+func (p Parser) badEntryError(e ast.Expr) error {
+	return p.error(e, "", "", fmt.Errorf("%w: unexpected %s (want a validator constructor such as `pkg.Func` or `pkg.Func[T]`)", ErrBadDecl, types.ExprString(e)))
+}
+
+// parseNamedType parses the named type t (e.g. `money.Money`), and returns
+// it qualified by the import path (e.g. `example.com/money.Money`). The
+// packages not imported (e.g. `time`) are assumed to be standard ones.
+func (p Parser) parseNamedType(t string) (string, error) {
+	e, err := parser.ParseExpr(t)
+	if err != nil {
+		return "", fmt.Errorf("unknown type %q", t)
+	}
+	sel, ok := e.(*ast.SelectorExpr)
+	if !ok {
+		return "", fmt.Errorf("unknown type %q", t)
+	}
+	x, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return "", fmt.Errorf("unknown type %q", t)
+	}
+
+	path := x.Name
+	if imported, ok := p.imports[x.Name]; ok {
+		path = imported
+	}
+	return path + "." + sel.Sel.Name, nil
+}
+
+// parseArgs parses the argument declarations s, which are separated by
+// commas. Each argument is in the form of `[name:]kind[...][=default]`.
+func parseArgs(s string) ([]Arg, error) {
+	parts := strings.Split(s, ",")
+
+	var args []Arg
+	for i, part := range parts {
+		var arg Arg
+
+		spec := part
+		if j := strings.Index(spec, "="); j != -1 {
+			spec, arg.Default = spec[:j], spec[j+1:]
+			if arg.Default == "" {
+				return nil, fmt.Errorf("empty default value in %q", part)
+			}
+		}
+		if j := strings.Index(spec, ":"); j != -1 {
+			arg.Name, spec = spec[:j], spec[j+1:]
+			if !token.IsIdentifier(arg.Name) {
+				return nil, fmt.Errorf("bad argument name %q", arg.Name)
+			}
+		}
+		if strings.HasSuffix(spec, "...") {
+			arg.Variadic, spec = true, strings.TrimSuffix(spec, "...")
+		}
+		if !argKinds[spec] {
+			return nil, fmt.Errorf("unknown argument kind %q", spec)
+		}
+		arg.Kind = spec
+
+		switch {
+		case arg.Variadic && i != len(parts)-1:
+			return nil, fmt.Errorf("variadic argument %q must be the last one", part)
+		case arg.Variadic && arg.Default != "":
+			return nil, fmt.Errorf("variadic argument %q cannot have a default value", part)
+		case arg.Default == "" && !arg.Variadic && len(args) > 0 && args[len(args)-1].Default != "":
+			return nil, fmt.Errorf("argument %q without a default value follows the ones with default values", part)
+		}
+
+		args = append(args, arg)
+	}
+	return args, nil
+}
+
+// defaultImports returns the imports referenced by the default values of args.
+func (p Parser) defaultImports(args []Arg) map[string]string {
+	var imports map[string]string
+	for _, arg := range args {
+		e, err := parser.ParseExpr(arg.Default)
+		if err != nil {
+			continue
+		}
+		ast.Inspect(e, func(n ast.Node) bool {
+			sel, ok := n.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			if x, ok := sel.X.(*ast.Ident); ok && p.imports[x.Name] != "" {
+				if imports == nil {
+					imports = make(map[string]string)
+				}
+				imports[x.Name] = p.imports[x.Name]
+			}
+			return false
+		})
+	}
+	return imports
+}
+
+// argNum returns the range of the number of arguments declared by args.
+func argNum(args []Arg) Range {
+	r := Range{Max: len(args)}
+	for _, arg := range args {
+		if arg.Variadic {
+			r.Max = math.MaxInt
+			continue
+		}
+		if arg.Default == "" {
+			r.Min++
+		}
+	}
+	return r
+}
+
 func (p Parser) parseSelector(e *ast.SelectorExpr) (qualifier, name string, pos token.Pos, err error) {
 	x, ok := e.X.(*ast.Ident)
 	if !ok {
-		return "", "", 0, ErrBadDecl
+		return "", "", 0, p.badEntryError(e)
 	}
 	return x.Name, e.Sel.Name, e.Sel.NamePos, nil
 }
 
-func (p Parser) getComment(pos token.Pos) string {
+// getComment returns the comment in the line preceding pos, if any.
+func (p Parser) getComment(pos token.Pos) *ast.Comment {
 	for _, comment := range p.comments {
 		c := comment.List[0]
 		if p.fset.Position(c.Slash).Line+1 == p.fset.Position(pos).Line {
-			return c.Text
+			return c
 		}
 	}
-	return ""
-}
-
-func mustAtoi(s string) int {
-	n, err := strconv.Atoi(s)
-	if err != nil {
-		panic(err)
-	}
-	return n
+	return nil
 }
@@ -16,7 +16,25 @@ func TestParseConverters(t *testing.T) {
 
 	want := []*decl.Validator{
 		{
-			Import:       "github.com/RussellLuo/validating",
+			Import:       "github.com/protogodev/validate/vtime",
+			Qualifier:    "vtime",
+			Name:         "Nonzero",
+			IsGeneric:    false,
+			Alias:        "nonzero",
+			AllowedTypes: []string{"time"},
+			ArgNum:       decl.Range{Min: 0, Max: 0},
+		},
+		{
+			Import:       "github.com/protogodev/validate/vtime",
+			Qualifier:    "vtime",
+			Name:         "Zero",
+			IsGeneric:    false,
+			Alias:        "zero",
+			AllowedTypes: []string{"time"},
+			ArgNum:       decl.Range{Min: 0, Max: 0},
+		},
+		{
+			Import:       "github.com/RussellLuo/validating/v3",
 			Qualifier:    "v",
 			Name:         "Nonzero",
 			IsGeneric:    true,
@@ -25,7 +43,7 @@ func TestParseConverters(t *testing.T) {
 			ArgNum:       decl.Range{Min: 0, Max: 0},
 		},
 		{
-			Import:       "github.com/RussellLuo/validating",
+			Import:       "github.com/RussellLuo/validating/v3",
 			Qualifier:    "v",
 			Name:         "Zero",
 			IsGeneric:    true,
@@ -34,121 +52,146 @@ func TestParseConverters(t *testing.T) {
 			ArgNum:       decl.Range{Min: 0, Max: 0},
 		},
 		{
-			Import:       "github.com/RussellLuo/validating",
+			Import:       "github.com/RussellLuo/validating/v3",
 			Qualifier:    "v",
 			Name:         "LenString",
 			IsGeneric:    false,
 			Alias:        "len",
 			AllowedTypes: []string{"string"},
-			ArgNum:       decl.Range{Min: 2, Max: 2},
+			ArgNum:       decl.Range{Min: 0, Max: 2},
+			Args: []decl.Arg{
+				{Name: "min", Kind: "int", Default: "0"},
+				{Name: "max", Kind: "int", Default: "math.MaxInt"},
+			},
+			DefaultImports: map[string]string{"math": "math"},
 		},
 		{
-			Import:       "github.com/RussellLuo/validating",
+			Import:       "github.com/RussellLuo/validating/v3",
 			Qualifier:    "v",
 			Name:         "LenSlice",
 			IsGeneric:    true,
 			Alias:        "len",
 			AllowedTypes: []string{"slice"},
-			ArgNum:       decl.Range{Min: 2, Max: 2},
+			ArgNum:       decl.Range{Min: 0, Max: 2},
+			Args: []decl.Arg{
+				{Name: "min", Kind: "int", Default: "0"},
+				{Name: "max", Kind: "int", Default: "math.MaxInt"},
+			},
+			DefaultImports: map[string]string{"math": "math"},
 		},
 		{
-			Import:       "github.com/RussellLuo/validating",
+			Import:       "github.com/RussellLuo/validating/v3",
 			Qualifier:    "v",
 			Name:         "RuneCount",
 			IsGeneric:    false,
-			Alias:        "runec",
-			AllowedTypes: []string{"string|bytes"},
-			ArgNum:       decl.Range{Min: 2, Max: 2},
+			Alias:        "runecnt",
+			AllowedTypes: []string{"string", "bytes"},
+			ArgNum:       decl.Range{Min: 0, Max: 2},
+			Args: []decl.Arg{
+				{Name: "min", Kind: "int", Default: "0"},
+				{Name: "max", Kind: "int", Default: "math.MaxInt"},
+			},
+			DefaultImports: map[string]string{"math": "math"},
 		},
 		{
-			Import:       "github.com/RussellLuo/validating",
+			Import:       "github.com/RussellLuo/validating/v3",
 			Qualifier:    "v",
 			Name:         "Eq",
 			IsGeneric:    true,
 			Alias:        "eq",
 			AllowedTypes: []string{"comparable"},
 			ArgNum:       decl.Range{Min: 1, Max: 1},
+			Args:         []decl.Arg{{Name: "value", Kind: "T"}},
 		},
 		{
-			Import:       "github.com/RussellLuo/validating",
+			Import:       "github.com/RussellLuo/validating/v3",
 			Qualifier:    "v",
 			Name:         "Ne",
 			IsGeneric:    true,
 			Alias:        "ne",
 			AllowedTypes: []string{"comparable"},
 			ArgNum:       decl.Range{Min: 1, Max: 1},
+			Args:         []decl.Arg{{Name: "value", Kind: "T"}},
 		},
 		{
-			Import:       "github.com/RussellLuo/validating",
+			Import:       "github.com/RussellLuo/validating/v3",
 			Qualifier:    "v",
 			Name:         "Gt",
 			IsGeneric:    true,
 			Alias:        "gt",
 			AllowedTypes: []string{"ordered"},
 			ArgNum:       decl.Range{Min: 1, Max: 1},
+			Args:         []decl.Arg{{Name: "value", Kind: "T"}},
 		},
 		{
-			Import:       "github.com/RussellLuo/validating",
+			Import:       "github.com/RussellLuo/validating/v3",
 			Qualifier:    "v",
 			Name:         "Gte",
 			IsGeneric:    true,
 			Alias:        "gte",
 			AllowedTypes: []string{"ordered"},
 			ArgNum:       decl.Range{Min: 1, Max: 1},
+			Args:         []decl.Arg{{Name: "value", Kind: "T"}},
 		},
 		{
-			Import:       "github.com/RussellLuo/validating",
+			Import:       "github.com/RussellLuo/validating/v3",
 			Qualifier:    "v",
 			Name:         "Lt",
 			IsGeneric:    true,
 			Alias:        "lt",
 			AllowedTypes: []string{"ordered"},
 			ArgNum:       decl.Range{Min: 1, Max: 1},
+			Args:         []decl.Arg{{Name: "value", Kind: "T"}},
 		},
 		{
-			Import:       "github.com/RussellLuo/validating",
+			Import:       "github.com/RussellLuo/validating/v3",
 			Qualifier:    "v",
 			Name:         "Lte",
 			IsGeneric:    true,
 			Alias:        "lte",
 			AllowedTypes: []string{"ordered"},
 			ArgNum:       decl.Range{Min: 1, Max: 1},
+			Args:         []decl.Arg{{Name: "value", Kind: "T"}},
 		},
 		{
-			Import:       "github.com/RussellLuo/validating",
+			Import:       "github.com/RussellLuo/validating/v3",
 			Qualifier:    "v",
 			Name:         "Range",
 			IsGeneric:    true,
 			Alias:        "xrange",
 			AllowedTypes: []string{"ordered"},
 			ArgNum:       decl.Range{Min: 2, Max: 2},
+			Args:         []decl.Arg{{Name: "min", Kind: "T"}, {Name: "max", Kind: "T"}},
 		},
 		{
-			Import:       "github.com/RussellLuo/validating",
+			Import:       "github.com/RussellLuo/validating/v3",
 			Qualifier:    "v",
 			Name:         "In",
 			IsGeneric:    true,
 			Alias:        "in",
-			AllowedTypes: []string{"ordered"},
+			AllowedTypes: []string{"comparable"},
 			ArgNum:       decl.Range{Min: 1, Max: math.MaxInt},
+			Args:         []decl.Arg{{Kind: "T"}, {Kind: "T", Variadic: true}},
 		},
 		{
-			Import:       "github.com/RussellLuo/validating",
+			Import:       "github.com/RussellLuo/validating/v3",
 			Qualifier:    "v",
 			Name:         "Nin",
 			IsGeneric:    true,
 			Alias:        "nin",
-			AllowedTypes: []string{"ordered"},
+			AllowedTypes: []string{"comparable"},
 			ArgNum:       decl.Range{Min: 1, Max: math.MaxInt},
+			Args:         []decl.Arg{{Kind: "T"}, {Kind: "T", Variadic: true}},
 		},
 		{
-			Import:       "github.com/RussellLuo/validating",
+			Import:       "github.com/RussellLuo/validating/v3",
 			Qualifier:    "v",
 			Name:         "Match",
 			IsGeneric:    false,
 			Alias:        "match",
-			AllowedTypes: []string{"string|bytes"},
+			AllowedTypes: []string{"string", "bytes"},
 			ArgNum:       decl.Range{Min: 1, Max: 1},
+			Args:         []decl.Arg{{Name: "pattern", Kind: "string"}},
 		},
 		{
 			Import:       "github.com/RussellLuo/vext",
@@ -176,11 +219,279 @@ func TestParseConverters(t *testing.T) {
 			Alias:        "time",
 			AllowedTypes: []string{"string"},
 			ArgNum:       decl.Range{Min: 1, Max: 1},
+			Args:         []decl.Arg{{Name: "layout", Kind: "string"}},
+		},
+		{
+			Import:       "github.com/protogodev/validate/vtime",
+			Qualifier:    "vtime",
+			Name:         "After",
+			IsGeneric:    false,
+			Alias:        "after",
+			AllowedTypes: []string{"time"},
+			ArgNum:       decl.Range{Min: 1, Max: 1},
+			Args:         []decl.Arg{{Name: "t", Kind: "time"}},
+		},
+		{
+			Import:       "github.com/protogodev/validate/vtime",
+			Qualifier:    "vtime",
+			Name:         "Before",
+			IsGeneric:    false,
+			Alias:        "before",
+			AllowedTypes: []string{"time"},
+			ArgNum:       decl.Range{Min: 1, Max: 1},
+			Args:         []decl.Arg{{Name: "t", Kind: "time"}},
+		},
+		{
+			Import:       "github.com/protogodev/validate/vtime",
+			Qualifier:    "vtime",
+			Name:         "Within",
+			IsGeneric:    false,
+			Alias:        "within",
+			AllowedTypes: []string{"time"},
+			ArgNum:       decl.Range{Min: 1, Max: 1},
+			Args:         []decl.Arg{{Name: "d", Kind: "duration"}},
+		},
+		{
+			Import:       "github.com/protogodev/validate/vtime",
+			Qualifier:    "vtime",
+			Name:         "Future",
+			IsGeneric:    false,
+			Alias:        "future",
+			AllowedTypes: []string{"time"},
+			ArgNum:       decl.Range{Min: 0, Max: 0},
 		},
+		{
+			Import:       "github.com/protogodev/validate/vtime",
+			Qualifier:    "vtime",
+			Name:         "Past",
+			IsGeneric:    false,
+			Alias:        "past",
+			AllowedTypes: []string{"time"},
+			ArgNum:       decl.Range{Min: 0, Max: 0},
+		},
+	}
+
+	if !cmp.Equal(got, want) {
+		diff := cmp.Diff(got, want)
+		t.Errorf("Want - Got: %s", diff)
+	}
+}
+
+func TestParse_Ctx(t *testing.T) {
+	src := `package custom
+
+import (
+	"example.com/tenant"
+)
+
+var _ = []any{
+	// ctx=true type=string args=0 example="acme:123"
+	tenant.Tenant,
+}
+`
+	got, err := decl.Parse(src)
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
 	}
 
+	want := []*decl.Validator{
+		{
+			Import:       "example.com/tenant",
+			Qualifier:    "tenant",
+			Name:         "Tenant",
+			Alias:        "tenant",
+			AllowedTypes: []string{"string"},
+			ArgNum:       decl.Range{Min: 0, Max: 0},
+			Ctx:          true,
+			Example:      `"acme:123"`,
+		},
+	}
 	if !cmp.Equal(got, want) {
 		diff := cmp.Diff(got, want)
 		t.Errorf("Want - Got: %s", diff)
 	}
 }
+
+func TestParse_Args(t *testing.T) {
+	src := `package custom
+
+import (
+	"math"
+
+	"example.com/c"
+)
+
+var _ = []any{
+	// type=string args=min:int,max:int=math.MaxInt
+	c.Between,
+
+	// type=comparable args=string,T...
+	c.OneOf,
+}
+`
+	got, err := decl.Parse(src)
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+
+	want := []*decl.Validator{
+		{
+			Import:       "example.com/c",
+			Qualifier:    "c",
+			Name:         "Between",
+			Alias:        "between",
+			AllowedTypes: []string{"string"},
+			ArgNum:       decl.Range{Min: 1, Max: 2},
+			Args: []decl.Arg{
+				{Name: "min", Kind: "int"},
+				{Name: "max", Kind: "int", Default: "math.MaxInt"},
+			},
+			DefaultImports: map[string]string{"math": "math"},
+		},
+		{
+			Import:       "example.com/c",
+			Qualifier:    "c",
+			Name:         "OneOf",
+			Alias:        "oneof",
+			AllowedTypes: []string{"comparable"},
+			ArgNum:       decl.Range{Min: 1, Max: math.MaxInt},
+			Args:         []decl.Arg{{Kind: "string"}, {Kind: "T", Variadic: true}},
+		},
+	}
+	if !cmp.Equal(got, want) {
+		diff := cmp.Diff(got, want)
+		t.Errorf("Want - Got: %s", diff)
+	}
+}
+
+func TestParse_NamedTypes(t *testing.T) {
+	src := `package custom
+
+import (
+	"example.com/c"
+	"example.com/money/v2"
+)
+
+var _ = []any{
+	// type=time.Time|time.Duration args=0
+	c.Recent,
+
+	// type=money.Money|integer args=0
+	c.Positive,
+}
+`
+	got, err := decl.Parse(src)
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+
+	var gotTypes []decl.Types
+	for _, v := range got {
+		gotTypes = append(gotTypes, v.AllowedTypes)
+	}
+	want := []decl.Types{
+		{"time.Time", "time.Duration"},
+		{"example.com/money/v2.Money", "integer"},
+	}
+	if !cmp.Equal(gotTypes, want) {
+		diff := cmp.Diff(gotTypes, want)
+		t.Errorf("Want - Got: %s", diff)
+	}
+}
+
+func TestParse_BadDecl(t *testing.T) {
+	src := func(body string) string {
+		return "package custom\n\nimport \"example.com/c\"\n\nvar _ = []any{\n" + body + "\n}\n"
+	}
+
+	tests := []struct {
+		name       string
+		in         string
+		wantErrStr string
+	}{
+		{
+			name:       "syntax error",
+			in:         src("\tc.UUID("),
+			wantErrStr: "custom.go:7:1: expected operand, found '}'",
+		},
+		{
+			name:       "not composite literal",
+			in:         "package custom\n\nvar _ = 1\n",
+			wantErrStr: "custom.go:3: bad declaration of var `_`: want a composite literal (e.g. `[]any{...}`)",
+		},
+		{
+			name:       "bad entry",
+			in:         src("\t// type=string\n\tUUID,"),
+			wantErrStr: "custom.go:7: bad declaration of var `_`: unexpected UUID (want a validator constructor such as `pkg.Func` or `pkg.Func[T]`)",
+		},
+		{
+			name:       "not imported",
+			in:         src("\t// type=string\n\tx.UUID,"),
+			wantErrStr: "custom.go:7: x.UUID: package x is not imported",
+		},
+		{
+			name:       "missing comment",
+			in:         src("\tc.UUID,"),
+			wantErrStr: "custom.go:6: c.UUID: missing comment (e.g. `// type=string args=0`)",
+		},
+		{
+			name:       "bad field",
+			in:         src("\t// type=string args\n\tc.UUID,"),
+			wantErrStr: `custom.go:6: c.UUID: bad field "args" (want key=value)`,
+		},
+		{
+			name:       "unknown key",
+			in:         src("\t// type=string arg=1\n\tc.UUID,"),
+			wantErrStr: `custom.go:6: c.UUID: unknown key "arg"`,
+		},
+		{
+			name:       "missing type",
+			in:         src("\t// args=1\n\tc.UUID,"),
+			wantErrStr: `custom.go:6: c.UUID: missing key "type"`,
+		},
+		{
+			name:       "unknown type",
+			in:         src("\t// type=string|text\n\tc.UUID,"),
+			wantErrStr: `custom.go:6: c.UUID: type: unknown type "text"`,
+		},
+		{
+			name:       "bad name",
+			in:         src("\t// name=is-uuid type=string\n\tc.UUID,"),
+			wantErrStr: `custom.go:6: c.UUID: name: bad name "is-uuid"`,
+		},
+		{
+			name:       "bad ctx",
+			in:         src("\t// type=string ctx=yes\n\tc.UUID,"),
+			wantErrStr: `custom.go:6: c.UUID: ctx: bad value "yes" (want true or false)`,
+		},
+		{
+			name:       "bad example",
+			in:         src("\t// type=string example=abc\n\tc.UUID,"),
+			wantErrStr: `custom.go:6: c.UUID: example: bad value "abc" (want a Go literal, e.g. "abc" or 10)`,
+		},
+		{
+			name:       "bad args",
+			in:         src("\t// type=string args=x\n\tc.UUID,"),
+			wantErrStr: `custom.go:6: c.UUID: args: unknown argument kind "x"`,
+		},
+		{
+			name:       "misplaced variadic args",
+			in:         src("\t// type=string args=T...,int\n\tc.UUID,"),
+			wantErrStr: `custom.go:6: c.UUID: args: variadic argument "T..." must be the last one`,
+		},
+		{
+			name:       "default args not trailing",
+			in:         src("\t// type=string args=a:int=1,b:int\n\tc.UUID,"),
+			wantErrStr: `custom.go:6: c.UUID: args: argument "b:int" without a default value follows the ones with default values`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decl.ParseFile("custom.go", tt.in, nil)
+			if err == nil || err.Error() != tt.wantErrStr {
+				t.Errorf("Err: got (%v), want (%#v)", err, tt.wantErrStr)
+			}
+		})
+	}
+}
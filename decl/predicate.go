@@ -4,54 +4,160 @@ import (
 	"go/types"
 )
 
-func IsStruct(typ types.Type) bool {
-	_, ok := typ.Underlying().(*types.Struct)
-	return ok
+// CoreType returns the core type of typ, which is the underlying type of typ
+// if typ is not a type parameter. For a type parameter, it is the single
+// underlying type of all the types in its type set, or nil if there is no
+// such type (e.g. `~int | ~string`, or `any`).
+func CoreType(typ types.Type) types.Type {
+	tparam, ok := typ.(*types.TypeParam)
+	if !ok {
+		return typ.Underlying()
+	}
+
+	var core types.Type
+	for _, u := range termUnderlyings(tparam) {
+		if core != nil && !types.Identical(core, u) {
+			return nil
+		}
+		core = u
+	}
+	return core
 }
 
-func IsComparable(typ types.Type) bool {
-	_, ok := typ.Underlying().(*types.Basic)
-	return ok
+// termUnderlyings returns the underlying types of the terms in the type set
+// of the type parameter tparam, which is empty if the type set is not
+// restricted by any term (e.g. `any` or `comparable`).
+func termUnderlyings(tparam *types.TypeParam) []types.Type {
+	iface, ok := tparam.Constraint().Underlying().(*types.Interface)
+	if !ok {
+		return nil
+	}
+
+	var terms []types.Type
+	collectTerms(iface, &terms)
+	for i, t := range terms {
+		terms[i] = t.Underlying()
+	}
+	return terms
 }
 
-func IsOrdered(typ types.Type) bool {
-	switch t := typ.Underlying().(type) {
-	case *types.Basic:
-		switch t.Kind() {
-		case types.Int, types.Int8, types.Int16, types.Int32, types.Int64,
-			types.Uint, types.Uint8, types.Uint16, types.Uint32, types.Uint64, types.Uintptr,
-			types.Float32, types.Float64,
-			types.String:
-			return true
+// all reports whether all the types, which typ may be, satisfy pred. For a
+// type parameter, these are the types in its type set.
+func all(typ types.Type, pred func(underlying types.Type) bool) bool {
+	tparam, ok := typ.(*types.TypeParam)
+	if !ok {
+		return pred(typ.Underlying())
+	}
+
+	terms := termUnderlyings(tparam)
+	if len(terms) == 0 {
+		return false
+	}
+	for _, t := range terms {
+		if !pred(t) {
+			return false
 		}
 	}
-	return false
+	return true
 }
 
-func IsString(typ types.Type) bool {
-	switch t := typ.Underlying().(type) {
-	case *types.Basic:
-		if t.Kind() == types.String {
-			return true
-		}
+// hasInfo returns a predicate reporting whether a type is a basic type with
+// any of the given properties.
+func hasInfo(info types.BasicInfo) func(types.Type) bool {
+	return func(t types.Type) bool {
+		b, ok := t.(*types.Basic)
+		return ok && b.Info()&info != 0
 	}
-	return false
+}
+
+// IsComparable reports whether values of typ can be compared by `==`, e.g.
+// basic types, pointers, channels, and structs or arrays of comparable types.
+func IsComparable(typ types.Type) bool {
+	return types.Comparable(typ)
+}
+
+// IsOrdered reports whether values of typ can be ordered by `<`, i.e.
+// integers, floating-point numbers and strings (including the untyped ones).
+func IsOrdered(typ types.Type) bool {
+	return all(typ, hasInfo(types.IsOrdered))
+}
+
+// IsNumber reports whether typ is an integer or a floating-point number.
+func IsNumber(typ types.Type) bool {
+	return all(typ, hasInfo(types.IsInteger|types.IsFloat))
+}
+
+// IsInteger reports whether typ is a (signed or unsigned) integer.
+func IsInteger(typ types.Type) bool {
+	return all(typ, hasInfo(types.IsInteger))
+}
+
+// IsFloat reports whether typ is a floating-point number.
+func IsFloat(typ types.Type) bool {
+	return all(typ, hasInfo(types.IsFloat))
+}
+
+// IsNumeric reports whether typ is an integer, a floating-point number or
+// a complex number.
+func IsNumeric(typ types.Type) bool {
+	return all(typ, hasInfo(types.IsNumeric))
+}
+
+func IsString(typ types.Type) bool {
+	return all(typ, hasInfo(types.IsString))
 }
 
 func IsBytes(typ types.Type) bool {
-	switch t := typ.Underlying().(type) {
-	case *types.Slice:
-		switch et := t.Elem().(type) {
-		case *types.Basic:
-			if et.Kind() == types.Byte {
-				return true
-			}
-		}
+	s, ok := CoreType(typ).(*types.Slice)
+	if !ok {
+		return false
 	}
-	return false
+	b, ok := s.Elem().Underlying().(*types.Basic)
+	return ok && b.Kind() == types.Byte
 }
 
 func IsSlice(typ types.Type) bool {
-	_, ok := typ.Underlying().(*types.Slice)
+	_, ok := CoreType(typ).(*types.Slice)
 	return ok
 }
+
+func IsArray(typ types.Type) bool {
+	_, ok := CoreType(typ).(*types.Array)
+	return ok
+}
+
+func IsMap(typ types.Type) bool {
+	_, ok := CoreType(typ).(*types.Map)
+	return ok
+}
+
+func IsPointer(typ types.Type) bool {
+	_, ok := CoreType(typ).(*types.Pointer)
+	return ok
+}
+
+func IsStruct(typ types.Type) bool {
+	_, ok := CoreType(typ).(*types.Struct)
+	return ok
+}
+
+// IsTime reports whether typ is time.Time.
+func IsTime(typ types.Type) bool {
+	return IsNamed(typ, "time", "Time")
+}
+
+// IsDuration reports whether typ is time.Duration.
+func IsDuration(typ types.Type) bool {
+	return IsNamed(typ, "time", "Duration")
+}
+
+// IsNamed reports whether typ is the named type declared as name in the
+// package whose import path is pkgPath.
+func IsNamed(typ types.Type, pkgPath, name string) bool {
+	named, ok := typ.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == pkgPath && obj.Name() == name
+}
@@ -0,0 +1,90 @@
+package decl_test
+
+import (
+	"go/types"
+	"testing"
+
+	"github.com/protogodev/validate/decl"
+)
+
+func TestTypes_Allow(t *testing.T) {
+	pkg := checkPackage(t, "example.com/p", `package p
+
+import "time"
+
+type Name string
+
+type Key struct{ ID int }
+
+type Func struct{ F func() }
+
+type Integer interface{ ~int | ~int64 }
+
+type Bytes interface{ ~[]byte }
+
+type Mixed interface{ ~int | ~string }
+
+func Generic[I Integer, B Bytes, M Mixed]() {}
+
+var (
+	name     Name
+	key      Key
+	fn       Func
+	ptr      *int
+	ch       chan int
+	arr      [2]Key
+	c        complex128
+	u8       uint8
+	f32      float32
+	t        time.Time
+	d        time.Duration
+	bs       []byte
+	m        map[string]int
+)
+`)
+	lookup := func(name string) types.Type {
+		return pkg.Scope().Lookup(name).Type()
+	}
+	tparams := lookup("Generic").(*types.Signature).TypeParams()
+
+	tests := []struct {
+		in    string
+		typ   types.Type
+		allow []string
+		deny  []string
+	}{
+		{"name", lookup("name"), []string{"comparable", "ordered", "string"}, []string{"number", "bytes"}},
+		{"key", lookup("key"), []string{"comparable", "struct"}, []string{"ordered", "time"}},
+		{"fn", lookup("fn"), []string{"struct"}, []string{"comparable"}},
+		{"ptr", lookup("ptr"), []string{"comparable", "pointer"}, []string{"ordered"}},
+		{"ch", lookup("ch"), []string{"comparable"}, []string{"pointer"}},
+		{"arr", lookup("arr"), []string{"comparable", "array"}, []string{"slice"}},
+		{"c", lookup("c"), []string{"comparable", "numeric"}, []string{"ordered", "number"}},
+		{"u8", lookup("u8"), []string{"ordered", "number", "integer", "numeric"}, []string{"float"}},
+		{"f32", lookup("f32"), []string{"ordered", "number", "float"}, []string{"integer"}},
+		{"t", lookup("t"), []string{"comparable", "struct", "time", "time.Time"}, []string{"duration", "example.com/p.Key"}},
+		{"d", lookup("d"), []string{"integer", "duration", "time.Duration"}, []string{"time"}},
+		{"bs", lookup("bs"), []string{"bytes", "slice"}, []string{"comparable", "string"}},
+		{"m", lookup("m"), []string{"map"}, []string{"comparable"}},
+		{"key (named)", lookup("key"), []string{"example.com/p.Key"}, []string{"example.com/q.Key"}},
+		{"I", tparams.At(0), []string{"comparable", "integer", "ordered"}, []string{"float", "string"}},
+		{"B", tparams.At(1), []string{"bytes", "slice"}, []string{"string"}},
+		{"M", tparams.At(2), []string{"comparable", "ordered"}, []string{"integer", "string"}},
+		{"untyped", types.Typ[types.UntypedFloat], []string{"ordered", "float"}, []string{"integer"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			for _, a := range tt.allow {
+				if !(decl.Types{a}).Allow(tt.typ) {
+					t.Errorf("%s should allow %s", a, tt.typ)
+				}
+			}
+			for _, d := range tt.deny {
+				if (decl.Types{d}).Allow(tt.typ) {
+					t.Errorf("%s should not allow %s", d, tt.typ)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package decl
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/types"
+	"strings"
+)
+
+// timePkg is the package of the sample types time.Time and time.Duration.
+var timePkg = types.NewPackage("time", "time")
+
+// sampleTypes are the representatives of the type classes, which are used
+// to find out whether two sets of allowed types overlap.
+var sampleTypes = []types.Type{
+	types.Typ[types.Bool],
+	types.Typ[types.Int],
+	types.Typ[types.Float64],
+	types.Typ[types.Complex128],
+	types.Typ[types.String],
+	types.NewSlice(types.Typ[types.Byte]),
+	types.NewSlice(types.Typ[types.Int]),
+	types.NewArray(types.Typ[types.Int], 1),
+	types.NewArray(types.NewSlice(types.Typ[types.Int]), 1),
+	types.NewMap(types.Typ[types.String], types.Typ[types.Int]),
+	types.NewPointer(types.Typ[types.Int]),
+	types.NewChan(types.SendRecv, types.Typ[types.Int]),
+	types.NewStruct(nil, nil),
+	types.NewNamed(types.NewTypeName(0, timePkg, "Time", nil), types.NewStruct(nil, nil), nil),
+	types.NewNamed(types.NewTypeName(0, timePkg, "Duration", nil), types.Typ[types.Int64], nil),
+}
+
+// Overlap reports whether there is a type allowed by both ts and other. Since
+// the underlying types of the named types (except time.Time and time.Duration)
+// are unknown, a named type is only considered to overlap with itself and
+// `any`.
+func (ts Types) Overlap(other Types) bool {
+	for _, typ := range sampleTypes {
+		if ts.Allow(typ) && other.Allow(typ) {
+			return true
+		}
+	}
+
+	for _, t := range ts {
+		for _, o := range other {
+			if t == o || t == "any" || o == "any" {
+				// Only the named types may get here, since the type classes
+				// have been handled above.
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Resolve merges the builtin and custom declarations into a table keyed by
+// the validator alias, in which the declarations of each alias are ordered
+// by precedence:
+//
+//   - A custom declaration takes precedence over the builtin ones of the same
+//     alias, whose allowed types overlap with its own. These builtin ones are
+//     returned in shadows keyed by the custom one, and are expected to be
+//     acknowledged by `override=true` (see Validator.Override).
+//   - Two custom declarations of the same alias, whose allowed types overlap,
+//     are ambiguous. Whereas the builtin ones take precedence in the order of
+//     declaration (e.g. `nonzero` for time.Time precedes the one for all the
+//     comparable types).
+//
+// The declarations are left untouched, so they can be resolved again (e.g.
+// along with more custom ones).
+func Resolve(builtin, custom []*Validator) (decls map[string][]*Validator, shadows map[*Validator][]*Validator, err error) {
+	if err := checkAmbiguity(custom); err != nil {
+		return nil, nil, err
+	}
+
+	decls = make(map[string][]*Validator)
+	for _, d := range custom {
+		decls[d.Alias] = append(decls[d.Alias], d)
+	}
+
+	shadows = make(map[*Validator][]*Validator)
+	for _, d := range builtin {
+		for _, c := range custom {
+			if c.Alias == d.Alias && c.AllowedTypes.Overlap(d.AllowedTypes) {
+				shadows[c] = append(shadows[c], d)
+			}
+		}
+		decls[d.Alias] = append(decls[d.Alias], d)
+	}
+
+	for _, d := range custom {
+		if d.Override && len(shadows[d]) == 0 {
+			return nil, nil, fmt.Errorf("validator `%s` (%s): override=true, but there is no builtin `%s` to override", d.Alias, d.Entry(), d.Alias)
+		}
+	}
+
+	return decls, shadows, nil
+}
+
+// checkAmbiguity returns an error if two declarations in decls have the same
+// alias and overlapping allowed types.
+func checkAmbiguity(decls []*Validator) error {
+	for i, d := range decls {
+		for _, other := range decls[:i] {
+			if d.Alias == other.Alias && d.AllowedTypes.Overlap(other.AllowedTypes) {
+				return fmt.Errorf("ambiguous validator `%s`: both %s (type=%s) and %s (type=%s) accept the same types",
+					d.Alias, other.Entry(), strings.Join(other.AllowedTypes, "|"), d.Entry(), strings.Join(d.AllowedTypes, "|"))
+			}
+		}
+	}
+	return nil
+}
+
+// Requalify renames the qualifiers in v per renames, which maps the old
+// qualifiers to the new ones, including those referenced by the default
+// values of its arguments (e.g. `math.MaxInt`). It is used to resolve the
+// collisions between the qualifiers of different packages.
+func Requalify(v *Validator, renames map[string]string) {
+	if q, ok := renames[v.Qualifier]; ok {
+		v.Qualifier = q
+	}
+
+	renamed := make(map[string]string)
+	imports := make(map[string]string, len(v.DefaultImports))
+	for old, path := range v.DefaultImports {
+		if q, ok := renames[old]; ok {
+			imports[q] = path
+			renamed[old] = q
+			continue
+		}
+		imports[old] = path
+	}
+	if len(renamed) == 0 {
+		return
+	}
+	v.DefaultImports = imports
+
+	for i, arg := range v.Args {
+		e, err := parser.ParseExpr(arg.Default)
+		if err != nil {
+			continue
+		}
+		ast.Inspect(e, func(n ast.Node) bool {
+			sel, ok := n.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			if x, ok := sel.X.(*ast.Ident); ok {
+				if q, ok := renamed[x.Name]; ok {
+					x.Name = q
+				}
+			}
+			return false
+		})
+		v.Args[i].Default = types.ExprString(e)
+	}
+}
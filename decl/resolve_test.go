@@ -0,0 +1,120 @@
+package decl_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/protogodev/validate/decl"
+)
+
+func TestTypes_Overlap(t *testing.T) {
+	tests := []struct {
+		a, b decl.Types
+		want bool
+	}{
+		{decl.Types{"string"}, decl.Types{"string", "bytes"}, true},
+		{decl.Types{"string"}, decl.Types{"slice"}, false},
+		{decl.Types{"bytes"}, decl.Types{"slice"}, true},
+		{decl.Types{"number"}, decl.Types{"ordered"}, true},
+		{decl.Types{"comparable"}, decl.Types{"map"}, false},
+		{decl.Types{"any"}, decl.Types{"map"}, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.a.Overlap(tt.b); got != tt.want {
+			t.Errorf("%v.Overlap(%v): got (%v), want (%v)", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	builtin, err := decl.Parse(decl.BuiltinDecls)
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+
+	tests := []struct {
+		name        string
+		in          string
+		wantEntries map[string][]string
+		wantShadows map[string][]string
+		wantErrStr  string
+	}{
+		{
+			name: "shadow",
+			in: `// name=len type=string|bytes args=2
+	c.Len,
+
+	// name=email type=string override=true
+	c.Email,
+
+	// name=len type=map args=2
+	c.LenMap,`,
+			wantEntries: map[string][]string{
+				"len":   {"c.Len", "c.LenMap", "v.LenString", "v.LenSlice"},
+				"email": {"c.Email", "vext.Email"},
+			},
+			wantShadows: map[string][]string{
+				"c.Len":   {"v.LenString", "v.LenSlice"},
+				"c.Email": {"vext.Email"},
+			},
+		},
+		{
+			name: "ambiguous",
+			in: `// type=string
+	c.UUID,
+
+	// name=uuid type=comparable
+	c.UUIDv4,`,
+			wantErrStr: "ambiguous validator `uuid`: both c.UUID (type=string) and c.UUIDv4 (type=comparable) accept the same types",
+		},
+		{
+			name: "nothing to override",
+			in: `// type=string override=true
+	c.UUID,`,
+			wantErrStr: "validator `uuid` (c.UUID): override=true, but there is no builtin `uuid` to override",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			custom, err := decl.Parse("package custom\n\nimport \"example.com/c\"\n\nvar _ = []any{\n\t" + tt.in + "\n}\n")
+			if err != nil {
+				t.Fatalf("err: %v\n", err)
+			}
+
+			// Resolving the same declarations again must not duplicate the shadows.
+			_, _, _ = decl.Resolve(builtin, custom)
+
+			got, shadows, err := decl.Resolve(builtin, custom)
+			var gotErrStr string
+			if err != nil {
+				gotErrStr = err.Error()
+			}
+			if gotErrStr != tt.wantErrStr {
+				t.Fatalf("Err: got (%#v), want (%#v)", gotErrStr, tt.wantErrStr)
+			}
+			if err != nil {
+				return
+			}
+
+			for alias, want := range tt.wantEntries {
+				if gotEntries := entries(got[alias]); !cmp.Equal(gotEntries, want) {
+					t.Errorf("Entries of %q: got (%v), want (%v)", alias, gotEntries, want)
+				}
+			}
+			for _, d := range custom {
+				if gotShadows := entries(shadows[d]); !cmp.Equal(gotShadows, tt.wantShadows[d.Entry()]) {
+					t.Errorf("Shadows of %s: got (%v), want (%v)", d.Entry(), gotShadows, tt.wantShadows[d.Entry()])
+				}
+			}
+		})
+	}
+}
+
+func entries(decls []*decl.Validator) (s []string) {
+	for _, d := range decls {
+		s = append(s, d.Entry())
+	}
+	return s
+}
@@ -0,0 +1,350 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	goparser "go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/protogodev/protogo/parser/ifacetool"
+	"github.com/protogodev/validate/decl"
+	"golang.org/x/tools/go/packages"
+)
+
+// completeDecls holds the builtin declarations and the custom ones, along
+// with the imports they require.
+type completeDecls struct {
+	// validators maps the validator aliases to the declarations, in the
+	// order of precedence (see decl.Resolve).
+	validators map[string][]*decl.Validator
+	// shadows maps the custom declarations to the builtin ones shadowed by
+	// them (see decl.Resolve).
+	shadows map[*decl.Validator][]*decl.Validator
+	imports []ifacetool.Import
+
+	// custom holds the custom declarations per file.
+	custom []*declFile
+}
+
+// declFile is a file of custom declarations.
+type declFile struct {
+	filename   string
+	validators []*decl.Validator
+}
+
+// buildCompleteDecls builds the builtin declarations and the custom ones
+// from the files specified by patterns (see customDeclSources). The
+// qualifiers of the declarations are renamed if they collide with each
+// other, or with the ones already taken (e.g. by the source file).
+func buildCompleteDecls(patterns []string, taken []ifacetool.Import) (*completeDecls, error) {
+	builtin, err := decl.ParseFile("builtin.go", decl.BuiltinDecls, nil)
+	if err != nil {
+		return nil, fmt.Errorf("builtin declarations: %v", err)
+	}
+
+	sources, err := customDeclSources(patterns)
+	if err != nil {
+		return nil, fmt.Errorf("custom declarations: %v", err)
+	}
+
+	imp := sourceImporter()
+	decls := new(completeDecls)
+	var custom []*decl.Validator
+	for _, src := range sources {
+		validators, err := parseCustomDecls(src, imp)
+		if err != nil {
+			var declErr *decl.Error
+			if errors.As(err, &declErr) {
+				// Already positioned (e.g. `decl.go:8: ...`).
+				return nil, err
+			}
+			return nil, fmt.Errorf("custom declarations: %s: %v", src.filename, err)
+		}
+		decls.custom = append(decls.custom, &declFile{
+			filename:   src.filename,
+			validators: validators,
+		})
+		custom = append(custom, validators...)
+	}
+
+	decls.imports = qualifyDecls(append(builtin, custom...), taken)
+
+	decls.validators, decls.shadows, err = decl.Resolve(builtin, custom)
+	if err != nil {
+		return nil, err
+	}
+
+	return decls, nil
+}
+
+// qualifyDecls assigns each package, which is referenced by decls, a unique
+// qualifier, and returns the imports of these packages. The qualifier of a
+// package defaults to the one in the declarations, or the one in taken if
+// already imported. Otherwise, a numeric suffix (e.g. `validators2`) will be
+// appended to resolve a collision.
+func qualifyDecls(decls []*decl.Validator, taken []ifacetool.Import) []ifacetool.Import {
+	qualifiers := make(map[string]string) // import path -> qualifier
+	paths := make(map[string]string)      // qualifier -> import path
+	for _, i := range taken {
+		q := i.Alias
+		if q == "" {
+			q = importName(i.Path)
+		}
+		if _, ok := paths[q]; !ok {
+			qualifiers[i.Path] = q
+			paths[q] = i.Path
+		}
+	}
+
+	var imports []ifacetool.Import
+	qualify := func(q, path string) string {
+		if assigned, ok := qualifiers[path]; ok {
+			return assigned
+		}
+		assigned := q
+		for n := 2; paths[assigned] != ""; n++ {
+			assigned = q + strconv.Itoa(n)
+		}
+		qualifiers[path] = assigned
+		paths[assigned] = path
+		imports = append(imports, ifacetool.Import{Alias: assigned, Path: path})
+		return assigned
+	}
+
+	for _, d := range decls {
+		renames := make(map[string]string)
+		if q := qualify(d.Qualifier, d.Import); q != d.Qualifier {
+			renames[d.Qualifier] = q
+		}
+		for q, path := range d.DefaultImports {
+			if assigned := qualify(q, path); assigned != q {
+				renames[q] = assigned
+			}
+		}
+		if len(renames) > 0 {
+			decl.Requalify(d, renames)
+		}
+	}
+
+	return imports
+}
+
+// importName returns the default name of the package imported by path, which
+// is the last element of path excluding the major version suffix (if any).
+func importName(path string) string {
+	base := filepath.Base(path)
+	if strings.HasPrefix(base, "v") {
+		if _, err := strconv.Atoi(base[1:]); err == nil {
+			return filepath.Base(filepath.Dir(path))
+		}
+	}
+	return base
+}
+
+// declSource is the source of a custom declaration file, whose imports will
+// be resolved in dir.
+type declSource struct {
+	filename string
+	dir      string
+}
+
+// customDeclSources returns the custom declaration files specified by
+// patterns, each of which can be:
+//
+//   - A file, e.g. `./decl.go`.
+//   - A glob, e.g. `./validators/*.go`.
+//   - A directory, e.g. `./validators`.
+//   - A Go import path, e.g. `example.com/common/validators`.
+//
+// Only the files declaring validators (i.e. `var _ = []any{...}`) are
+// included if a glob, a directory or an import path is given.
+func customDeclSources(patterns []string) ([]declSource, error) {
+	var sources []declSource
+	seen := make(map[string]bool)
+	add := func(src declSource) error {
+		absFilename, err := filepath.Abs(src.filename)
+		if err != nil {
+			return err
+		}
+		if seen[absFilename] {
+			return nil
+		}
+		seen[absFilename] = true
+		src.dir = filepath.Dir(absFilename)
+		sources = append(sources, src)
+		return nil
+	}
+
+	for _, pattern := range patterns {
+		var srcs []declSource
+
+		switch {
+		case strings.ContainsAny(pattern, "*?["):
+			matches, err := filepath.Glob(pattern)
+			if err != nil {
+				return nil, err
+			}
+			for _, m := range matches {
+				if isDeclFile(m) {
+					srcs = append(srcs, declSource{filename: m})
+				}
+			}
+			if len(srcs) == 0 {
+				return nil, fmt.Errorf("found no declarations (i.e. `var _ = []any{...}`) in the files matching %q", pattern)
+			}
+
+		default:
+			info, err := os.Stat(pattern)
+			switch {
+			case err == nil && !info.IsDir():
+				srcs = append(srcs, declSource{filename: pattern})
+			case err == nil:
+				filenames, err := declFilesInDir(pattern)
+				if err != nil {
+					return nil, err
+				}
+				for _, f := range filenames {
+					srcs = append(srcs, declSource{filename: f})
+				}
+			case build.IsLocalImport(pattern) || filepath.IsAbs(pattern) || !os.IsNotExist(err):
+				return nil, err
+			default:
+				// An import path.
+				pkg, err := loadPackage(nil, "", pattern, packages.NeedName|packages.NeedFiles)
+				if err != nil {
+					return nil, err
+				}
+				if len(pkg.GoFiles) == 0 {
+					if len(pkg.Errors) > 0 {
+						return nil, pkg.Errors[0]
+					}
+					return nil, fmt.Errorf("no Go files in package %s", pattern)
+				}
+				filenames, err := declFilesInDir(filepath.Dir(pkg.GoFiles[0]))
+				if err != nil {
+					return nil, fmt.Errorf("package %s: %v", pattern, err)
+				}
+				for _, f := range filenames {
+					srcs = append(srcs, declSource{filename: f})
+				}
+			}
+		}
+
+		for _, src := range srcs {
+			if err := add(src); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return sources, nil
+}
+
+// declFilesInDir returns the files declaring validators in dir.
+func declFilesInDir(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var filenames []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		filename := filepath.Join(dir, name)
+		if isDeclFile(filename) {
+			filenames = append(filenames, filename)
+		}
+	}
+	if len(filenames) == 0 {
+		return nil, fmt.Errorf("found no declarations (i.e. `var _ = []any{...}`) in %s", dir)
+	}
+
+	sort.Strings(filenames)
+	return filenames, nil
+}
+
+// isDeclFile reports whether filename declares validators, i.e. holds the
+// declaration of the form `var _ = []any{...}`.
+func isDeclFile(filename string) bool {
+	f, err := goparser.ParseFile(token.NewFileSet(), filename, nil, goparser.SkipObjectResolution)
+	if err != nil {
+		return false
+	}
+
+	for _, d := range f.Decls {
+		gd, ok := d.(*ast.GenDecl)
+		if !ok || gd.Tok != token.VAR {
+			continue
+		}
+		for _, s := range gd.Specs {
+			vs, ok := s.(*ast.ValueSpec)
+			if !ok || vs.Names[0].Name != "_" || vs.Type != nil || len(vs.Values) == 0 {
+				continue
+			}
+			lit, ok := vs.Values[0].(*ast.CompositeLit)
+			if !ok {
+				continue
+			}
+			if at, ok := lit.Type.(*ast.ArrayType); ok && at.Len == nil {
+				switch elt := types.ExprString(at.Elt); elt {
+				case "any", "interface{}":
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// parseCustomDecls parses the custom declarations in src, whose metadata
+// will be inferred from the imported packages (see decl.ParseFile), which
+// are loaded by imp. The context-aware validators are also type-checked.
+func parseCustomDecls(src declSource, imp types.ImporterFrom) ([]*decl.Validator, error) {
+	b, err := os.ReadFile(src.filename)
+	if err != nil {
+		return nil, err
+	}
+
+	imported := make(map[string]*types.Package)
+	// The syntax errors, if any, will be reported by decl.ParseFile.
+	if f, err := goparser.ParseFile(token.NewFileSet(), src.filename, b, goparser.ImportsOnly); err == nil {
+		for _, i := range f.Imports {
+			path := strings.Trim(i.Path.Value, `"`)
+			p, err := imp.ImportFrom(path, src.dir, 0)
+			if err != nil {
+				return nil, err
+			}
+			imported[path] = p
+		}
+	}
+
+	decls, err := decl.ParseFile(src.filename, string(b), imported)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range decls {
+		if !d.Ctx {
+			continue
+		}
+		p, ok := imported[d.Import]
+		if !ok {
+			return nil, fmt.Errorf("validator `%s`: could not find package %q", d.Alias, d.Import)
+		}
+		if err := decl.CheckCtx(d, p); err != nil {
+			return nil, err
+		}
+	}
+	return decls, nil
+}
@@ -0,0 +1,214 @@
+package validate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/protogodev/protogo/parser/ifacetool"
+	"github.com/protogodev/validate/decl"
+)
+
+func TestQualifyDecls(t *testing.T) {
+	parse := func(src string) []*decl.Validator {
+		decls, err := decl.Parse(src)
+		if err != nil {
+			t.Fatalf("err: %v\n", err)
+		}
+		return decls
+	}
+
+	builtin := parse(`package builtin
+
+import (
+	"math"
+
+	v "github.com/RussellLuo/validating/v3"
+)
+
+var _ = []any{
+	// name=len type=string args=min:int=0,max:int=math.MaxInt
+	v.LenString,
+}
+`)
+	a := parse(`package a
+
+import (
+	"example.com/a/v"
+)
+
+var _ = []any{
+	// type=string
+	v.UUID,
+}
+`)
+	b := parse(`package b
+
+import (
+	"example.com/a/v"
+	math "example.com/b/mathx"
+	"example.com/b/validators"
+)
+
+var _ = []any{
+	// type=string args=max:int=math.MaxLen
+	validators.Short,
+
+	// type=string
+	v.Code,
+}
+`)
+
+	decls := append(append(builtin, a...), b...)
+	gotImports := qualifyDecls(decls, []ifacetool.Import{
+		{Path: "example.com/user/validators"},
+	})
+
+	wantImports := []ifacetool.Import{
+		{Alias: "v", Path: "github.com/RussellLuo/validating/v3"},
+		{Alias: "math", Path: "math"},
+		{Alias: "v2", Path: "example.com/a/v"},
+		{Alias: "validators2", Path: "example.com/b/validators"},
+		{Alias: "math2", Path: "example.com/b/mathx"},
+	}
+	if !cmp.Equal(gotImports, wantImports) {
+		diff := cmp.Diff(gotImports, wantImports)
+		t.Errorf("Want - Got: %s", diff)
+	}
+
+	gotEntries := entries(decls)
+	wantEntries := []string{"v.LenString", "v2.UUID", "validators2.Short", "v2.Code"}
+	if !cmp.Equal(gotEntries, wantEntries) {
+		diff := cmp.Diff(gotEntries, wantEntries)
+		t.Errorf("Want - Got: %s", diff)
+	}
+
+	short := decls[2]
+	if got, want := short.Args[0].Default, "math2.MaxLen"; got != want {
+		t.Errorf("Default: got (%q), want (%q)", got, want)
+	}
+	if got, want := short.DefaultImports, map[string]string{"math2": "example.com/b/mathx"}; !cmp.Equal(got, want) {
+		t.Errorf("DefaultImports: got (%v), want (%v)", got, want)
+	}
+}
+
+func TestCustomDeclSources(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"decl.go":              "package custom\n\nvar _ = []any{}\n",
+		"service.go":           "package custom\n\nvar _ Service = nil\n",
+		"common/decl.go":       "package common\n\nvar _ = []interface{}{}\n",
+		"common/decl_test.go":  "package common\n\nvar _ = []any{}\n",
+		"common/validators.go": "package common\n",
+		"empty/empty.go":       "package empty\n",
+	}
+	for name, content := range files {
+		filename := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatalf("err: %v\n", err)
+		}
+		if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+			t.Fatalf("err: %v\n", err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		in         []string
+		want       []string
+		wantErrStr string
+	}{
+		{
+			name: "file",
+			in:   []string{"decl.go"},
+			want: []string{"decl.go"},
+		},
+		{
+			name: "directory",
+			in:   []string{".", "common"},
+			want: []string{"decl.go", "common/decl.go"},
+		},
+		{
+			name: "glob",
+			in:   []string{"*/*.go", "decl.go"},
+			want: []string{"common/decl.go", "common/decl_test.go", "decl.go"},
+		},
+		{
+			name:       "no match",
+			in:         []string{"*/*.txt"},
+			wantErrStr: "found no declarations (i.e. `var _ = []any{...}`) in the files matching \"$DIR/*/*.txt\"",
+		},
+		{
+			name:       "no declarations",
+			in:         []string{"empty"},
+			wantErrStr: "found no declarations (i.e. `var _ = []any{...}`) in $DIR/empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var in []string
+			for _, p := range tt.in {
+				in = append(in, filepath.Join(dir, p))
+			}
+			sources, err := customDeclSources(in)
+
+			var gotErrStr string
+			if err != nil {
+				gotErrStr = err.Error()
+			}
+			wantErrStr := strings.ReplaceAll(tt.wantErrStr, "$DIR", dir)
+			if gotErrStr != wantErrStr {
+				t.Fatalf("Err: got (%#v), want (%#v)", gotErrStr, wantErrStr)
+			}
+
+			var got []string
+			for _, src := range sources {
+				rel, _ := filepath.Rel(dir, src.filename)
+				got = append(got, filepath.ToSlash(rel))
+			}
+			if !cmp.Equal(got, tt.want) {
+				diff := cmp.Diff(got, tt.want)
+				t.Errorf("Want - Got: %s", diff)
+			}
+		})
+	}
+}
+
+func entries(decls []*decl.Validator) (s []string) {
+	for _, d := range decls {
+		s = append(s, d.Entry())
+	}
+	return s
+}
+
+func TestBuildCompleteDecls(t *testing.T) {
+	// The metadata of the custom validators is inferred from the imported
+	// package (e.g. Tenant is context-aware).
+	decls, err := buildCompleteDecls([]string{"examples/messaging/decl.go"}, nil)
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+
+	type info struct {
+		Entry string
+		Ctx   bool
+		Types []string
+	}
+	var got []info
+	for _, f := range decls.custom {
+		for _, d := range f.validators {
+			got = append(got, info{Entry: d.Entry(), Ctx: d.Ctx, Types: d.AllowedTypes})
+		}
+	}
+	want := []info{
+		{Entry: "customvalidator.UUID", Types: []string{"string"}},
+		{Entry: "customvalidator.Tenant", Ctx: true, Types: []string{"string"}},
+	}
+	if !cmp.Equal(got, want) {
+		diff := cmp.Diff(got, want)
+		t.Errorf("Want - Got: %s", diff)
+	}
+}
@@ -1,23 +1,57 @@
 package validate
 
 import (
+	"go/ast"
+	"go/token"
 	"regexp"
 	"strings"
 )
 
 var (
 	reHeader = regexp.MustCompile(`^\s@(\w+):\s*$`)
-	reOption = regexp.MustCompile(`^\s{2,}(\w+):\s*(.+)$`)
+	reOption = regexp.MustCompile(`^\s{2,}([\w.]+):\s*(.+)$`)
 )
 
-type Option struct{ K, V string }
+type Option struct {
+	K, V string
+
+	// Pos is the position of V in the source file, which is only available
+	// if the option is parsed by ParseCommentGroup.
+	Pos token.Pos
+}
 
 func ParseDoc(comments []string) map[string][]Option {
+	lines := make([]docLine, len(comments))
+	for i, c := range comments {
+		lines[i] = docLine{Text: c}
+	}
+	return parseDocLines(lines)
+}
+
+// ParseCommentGroup is like ParseDoc, but also records the positions of
+// the option values.
+func ParseCommentGroup(cg *ast.CommentGroup) map[string][]Option {
+	if cg == nil {
+		return map[string][]Option{}
+	}
+	lines := make([]docLine, len(cg.List))
+	for i, c := range cg.List {
+		lines[i] = docLine{Text: c.Text, Pos: c.Slash}
+	}
+	return parseDocLines(lines)
+}
+
+type docLine struct {
+	Text string
+	Pos  token.Pos
+}
+
+func parseDocLines(lines []docLine) map[string][]Option {
 	annos := make(map[string][]Option)
 
 	var headerName string
-	for _, comment := range comments {
-		c := strings.TrimPrefix(comment, "//")
+	for _, line := range lines {
+		c := strings.TrimPrefix(line.Text, "//")
 
 		result := reHeader.FindAllStringSubmatch(c, -1)
 		if len(result) > 0 {
@@ -25,13 +59,17 @@ func ParseDoc(comments []string) map[string][]Option {
 			continue
 		}
 
-		result = reOption.FindAllStringSubmatch(c, -1)
-		if len(result) > 0 {
+		index := reOption.FindStringSubmatchIndex(c)
+		if index != nil {
 			if headerName != "" {
-				annos[headerName] = append(annos[headerName], Option{
-					K: result[0][1],
-					V: result[0][2],
-				})
+				opt := Option{
+					K: c[index[2]:index[3]],
+					V: c[index[4]:index[5]],
+				}
+				if line.Pos.IsValid() {
+					opt.Pos = line.Pos + token.Pos(len(line.Text)-len(c)+index[4])
+				}
+				annos[headerName] = append(annos[headerName], opt)
 			}
 			continue
 		}
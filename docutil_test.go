@@ -49,6 +49,20 @@ func TestParseDoc(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "field path",
+			in: []string{
+				"// @header1:",
+				"//   key1.Field1: value1",
+				"//   key1.Field2.Field3: value2",
+			},
+			want: map[string][]validate.Option{
+				"header1": {
+					{K: "key1.Field1", V: "value1"},
+					{K: "key1.Field2.Field3", V: "value2"},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
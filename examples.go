@@ -0,0 +1,123 @@
+package validate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/protogodev/protogo/generator"
+	"github.com/protogodev/protogo/parser/ifacetool"
+	"github.com/protogodev/validate/decl"
+	"github.com/protogodev/validate/runtime"
+)
+
+// GenerateExamples generates the example values of the method params, which
+// satisfy the `@schema` annotations (see runtime.Registry.ExampleParams),
+// e.g. for the fixtures of integration tests. The examples are keyed by the
+// method names, and then by the param names.
+//
+// The custom validators are only satisfied by their examples (i.e. `example=`
+// in the declarations). The methods, whose params cannot be synthesized
+// (e.g. the ones with `@assert`, nested fields or unsupported types), are
+// skipped with warnings, while the unsatisfiable constraints are reported
+// as errors.
+func (g *Generator) GenerateExamples(data *ifacetool.Data) (*generator.File, error) {
+	completeDecls, err := buildCompleteDecls(g.Custom, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	registry := runtime.New()
+	var custom []*decl.Validator
+	for _, f := range completeDecls.custom {
+		custom = append(custom, f.validators...)
+	}
+	if err := registry.Declare(custom...); err != nil {
+		return nil, err
+	}
+
+	examples := make(map[string]map[string]interface{})
+	var errs []string
+	for _, method := range data.Methods {
+		params, err := methodExamples(registry, method, ParseDoc(method.Doc))
+		switch {
+		case errors.Is(err, runtime.ErrUnsatisfiable):
+			errs = append(errs, fmt.Sprintf("%s: %v", method.Name, err))
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "warning: %s: %v (skipped)\n", method.Name, err)
+		case params != nil:
+			examples[method.Name] = params
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.New(strings.Join(errs, "\n"))
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(examples); err != nil {
+		return nil, err
+	}
+
+	return &generator.File{
+		Name:    filepath.Join(g.OutDir, "validate_examples.json"),
+		Content: buf.Bytes(),
+	}, nil
+}
+
+// methodExamples synthesizes the params of the given method, whose
+// annotations are annos. It returns nil if the method has no `@schema`.
+func methodExamples(r *runtime.Registry, method *ifacetool.Method, annos map[string][]Option) (map[string]interface{}, error) {
+	opts := annos["schema"]
+	if len(opts) == 0 {
+		return nil, nil
+	}
+	if len(annos["assert"]) > 0 {
+		return nil, errors.New("cannot synthesize the params satisfying @assert")
+	}
+
+	params, _ := methodParams(method)
+	paramTypes := make(map[string]reflect.Type)
+	for _, p := range params {
+		if typ, ok := exampleType(p.Type); ok {
+			paramTypes[p.Name] = typ
+		}
+	}
+
+	rules := make(map[string]string)
+	for _, opt := range opts {
+		if _, ok := paramTypes[opt.K]; !ok {
+			return nil, fmt.Errorf("cannot synthesize %s (only the params of the basic types, times, durations, or the slices or pointers of them are supported)", opt.K)
+		}
+		if _, ok := rules[opt.K]; ok {
+			return nil, fmt.Errorf("cannot synthesize %s with multiple schemas", opt.K)
+		}
+		rules[opt.K] = opt.V
+	}
+
+	return r.ExampleParams(context.Background(), rules, paramTypes)
+}
+
+// exampleType returns the reflect type of typ, which is time.Time,
+// time.Duration, or supported by reflectType.
+func exampleType(typ types.Type) (reflect.Type, bool) {
+	if named, ok := typ.(*types.Named); ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "time" {
+		switch named.Obj().Name() {
+		case "Time":
+			return reflect.TypeOf(time.Time{}), true
+		case "Duration":
+			return reflect.TypeOf(time.Duration(0)), true
+		}
+	}
+	return reflectType(typ)
+}
@@ -8,28 +8,38 @@ import (
 	"regexp"
 
 	v "github.com/RussellLuo/validating/v3"
+	"github.com/protogodev/validate/verr"
 )
 
 func ValidateMiddleware(wrap func(error) error) func(Service) Service {
+	return ValidateMiddlewareWithReturns(wrap, nil)
+}
+
+func ValidateMiddlewareWithReturns(wrap, wrapReturns func(error) error) func(Service) Service {
 	return func(next Service) Service {
 		if wrap == nil {
 			wrap = func(err error) error { return err }
 		}
+		if wrapReturns == nil {
+			wrapReturns = func(err error) error { return err }
+		}
 		return validateMiddleware{
-			next: next,
-			wrap: wrap,
+			next:        next,
+			wrap:        wrap,
+			wrapReturns: wrapReturns,
 		}
 	}
 }
 
 type validateMiddleware struct {
-	next Service
-	wrap func(error) error
+	next        Service
+	wrap        func(error) error
+	wrapReturns func(error) error
 }
 
 func (mw validateMiddleware) SayHello(ctx context.Context, name string) (string, error) {
 	schema := v.Schema{
-		v.F("name", name): v.All(v.LenString(0, 10).Msg("bad length"), v.Match(regexp.MustCompile(`^\w+$`)).Msg("invalid format")),
+		v.F("name", name): v.All(verr.Leaf(verr.Info{Validator: "len", Args: []string{"0", "10"}}, v.LenString(0, 10).Msg("bad length")), verr.Leaf(verr.Info{Validator: "match", Args: []string{"^\\w+$"}}, v.Match(regexp.MustCompile(`^\w+$`)).Msg("invalid format"))),
 	}
 
 	if err := v.Validate(schema); err != nil {
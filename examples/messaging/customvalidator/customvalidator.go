@@ -1,7 +1,9 @@
 package customvalidator
 
 import (
+	"context"
 	"regexp"
+	"strings"
 
 	v "github.com/RussellLuo/validating/v3"
 )
@@ -11,3 +13,19 @@ var reUUID = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4
 func UUID() *v.MessageValidator {
 	return v.Is(reUUID.MatchString).Msg("invalid UUID")
 }
+
+type tenantKey struct{}
+
+// WithTenant returns a copy of ctx, which carries the tenant of the caller.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// Tenant checks whether the user ID (e.g. `acme:123`) belongs to the tenant
+// of the caller, which is carried by ctx.
+func Tenant(ctx context.Context) *v.MessageValidator {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return v.Is(func(userID string) bool {
+		return tenant != "" && strings.HasPrefix(userID, tenant+":")
+	}).Msg("does not belong to the tenant")
+}
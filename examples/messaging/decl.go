@@ -5,6 +5,9 @@ import (
 )
 
 var _ = []any{
-	// type=string args=0
+	// type=string jsonschema=x-uuid example="123e4567-e89b-12d3-a456-426614174000"
 	customvalidator.UUID,
+
+	// type=string example="acme:123"
+	customvalidator.Tenant,
 }
@@ -5,7 +5,8 @@ import (
 	"fmt"
 )
 
-//go:generate protogo validate --custom=./decl.go ./service.go Service
+//go:generate protogo validate --examples --custom=./decl.go ./service.go Service
+//go:generate protogo validate-jsonschema --custom=./decl.go ./service.go Service
 
 type Service interface {
 	// GetMessage get the specified message.
@@ -14,6 +15,23 @@ type Service interface {
 	//   userID: len(1, 10)
 	//   messageID: uuid
 	GetMessage(ctx context.Context, userID string, messageID string) (text string, err error)
+
+	// GetMessages get the specified messages in batch.
+	//
+	// @schema:
+	//   userID: len(1, 10)
+	//   messageIDs: len(1, 5) && each(uuid)
+	GetMessages(ctx context.Context, userID string, messageIDs []string) (texts []string, err error)
+
+	// CountMessages counts the messages sent within the given period (in Unix time).
+	//
+	// @schema:
+	//   userID: len(1, 20) && tenant
+	//   until: gt(since)
+	//
+	// @assert:
+	//   until: (until - since <= 86400).msg("must be within one day after since")
+	CountMessages(ctx context.Context, userID string, since, until int64) (count int, err error)
 }
 
 type Messaging struct{}
@@ -21,3 +39,15 @@ type Messaging struct{}
 func (m *Messaging) GetMessage(ctx context.Context, userID string, messageID string) (string, error) {
 	return fmt.Sprintf("user[%s]: message[%s]", userID, messageID), nil
 }
+
+func (m *Messaging) GetMessages(ctx context.Context, userID string, messageIDs []string) (texts []string, err error) {
+	for _, messageID := range messageIDs {
+		text, _ := m.GetMessage(ctx, userID, messageID)
+		texts = append(texts, text)
+	}
+	return texts, nil
+}
+
+func (m *Messaging) CountMessages(ctx context.Context, userID string, since, until int64) (count int, err error) {
+	return 0, nil
+}
@@ -3,8 +3,12 @@ package messaging_test
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 
+	v "github.com/RussellLuo/validating/v3"
 	"github.com/protogodev/validate/examples/messaging"
+	"github.com/protogodev/validate/examples/messaging/customvalidator"
 )
 
 func Example() {
@@ -15,10 +19,43 @@ func Example() {
 	fmt.Printf("text: %q, err: %v\n", text, err)
 
 	text, err = svc.GetMessage(context.Background(), "", "")
-	fmt.Printf("text: %q, err: %v\n", text, err)
+	fmt.Printf("text: %q, err: %s\n", text, errString(err))
+
+	texts, err := svc.GetMessages(context.Background(), "123", []string{"00000000-1111-2222-3333-001122334455", "x"})
+	fmt.Printf("texts: %q, err: %v\n", texts, err)
+
+	ctx := customvalidator.WithTenant(context.Background(), "acme")
+
+	_, err = svc.CountMessages(ctx, "acme:123", 100, 100)
+	fmt.Printf("err: %v\n", err)
+
+	_, err = svc.CountMessages(ctx, "acme:123", 100, 100+86401)
+	fmt.Printf("err: %v\n", err)
+
+	_, err = svc.CountMessages(ctx, "other:123", 100, 200)
+	fmt.Printf("err: %v\n", err)
 
 	// Output:
 	// text: "user[123]: message[00000000-1111-2222-3333-001122334455]", err: <nil>
-	// text: "", err: userID: INVALID(has an invalid length), messageID: INVALID(invalid UUID)
+	// text: "", err: messageID: INVALID(invalid UUID), userID: INVALID(has an invalid length)
+	// texts: [], err: messageIDs[1]: INVALID(invalid UUID)
+	// err: until: INVALID(must be greater than since)
+	// err: until: INVALID(must be within one day after since)
+	// err: userID: INVALID(does not belong to the tenant)
+}
+
+// errString returns the string of err with the field errors sorted, since
+// the validation order of the fields in a schema is not deterministic.
+func errString(err error) string {
+	errs, ok := err.(v.Errors)
+	if !ok {
+		return fmt.Sprint(err)
+	}
 
+	var strs []string
+	for _, e := range errs {
+		strs = append(strs, e.Error())
+	}
+	sort.Strings(strs)
+	return strings.Join(strs, ", ")
 }
@@ -8,29 +8,53 @@ import (
 
 	v "github.com/RussellLuo/validating/v3"
 	customvalidator "github.com/protogodev/validate/examples/messaging/customvalidator"
+	"github.com/protogodev/validate/verr"
 )
 
 func ValidateMiddleware(wrap func(error) error) func(Service) Service {
+	return ValidateMiddlewareWithReturns(wrap, nil)
+}
+
+func ValidateMiddlewareWithReturns(wrap, wrapReturns func(error) error) func(Service) Service {
 	return func(next Service) Service {
 		if wrap == nil {
 			wrap = func(err error) error { return err }
 		}
+		if wrapReturns == nil {
+			wrapReturns = func(err error) error { return err }
+		}
 		return validateMiddleware{
-			next: next,
-			wrap: wrap,
+			next:        next,
+			wrap:        wrap,
+			wrapReturns: wrapReturns,
 		}
 	}
 }
 
 type validateMiddleware struct {
-	next Service
-	wrap func(error) error
+	next        Service
+	wrap        func(error) error
+	wrapReturns func(error) error
+}
+
+func (mw validateMiddleware) CountMessages(ctx context.Context, userID string, since int64, until int64) (int, error) {
+	schema := v.Schema{
+		v.F("userID", userID): v.All(verr.Leaf(verr.Info{Validator: "len", Args: []string{"1", "20"}}, v.LenString(1, 20)), verr.Leaf(verr.Info{Validator: "tenant"}, customvalidator.Tenant(ctx))),
+		v.F("until", until):   verr.Leaf(verr.Info{Validator: "gt", Args: []string{"since"}}, v.Gt[int64](since).Msg("must be greater than since")),
+		v.F("until", until):   verr.Leaf(verr.Info{Validator: "assert", Args: []string{"until - since <= 86400"}}, v.Is(func(int64) bool { return until-since <= 86400 }).Msg("must be within one day after since")),
+	}
+
+	if err := v.Validate(schema); err != nil {
+		return 0, mw.wrap(err)
+	}
+
+	return mw.next.CountMessages(ctx, userID, since, until)
 }
 
 func (mw validateMiddleware) GetMessage(ctx context.Context, userID string, messageID string) (string, error) {
 	schema := v.Schema{
-		v.F("userID", userID):       v.LenString(1, 10),
-		v.F("messageID", messageID): customvalidator.UUID(),
+		v.F("userID", userID):       verr.Leaf(verr.Info{Validator: "len", Args: []string{"1", "10"}}, v.LenString(1, 10)),
+		v.F("messageID", messageID): verr.Leaf(verr.Info{Validator: "uuid"}, customvalidator.UUID()),
 	}
 
 	if err := v.Validate(schema); err != nil {
@@ -39,3 +63,21 @@ func (mw validateMiddleware) GetMessage(ctx context.Context, userID string, mess
 
 	return mw.next.GetMessage(ctx, userID, messageID)
 }
+
+func (mw validateMiddleware) GetMessages(ctx context.Context, userID string, messageIDs []string) ([]string, error) {
+	schema := v.Schema{
+		v.F("userID", userID): verr.Leaf(verr.Info{Validator: "len", Args: []string{"1", "10"}}, v.LenString(1, 10)),
+		v.F("messageIDs", messageIDs): v.All(verr.Leaf(verr.Info{Validator: "len", Args: []string{"1", "5"}}, v.LenSlice[[]string](1, 5)), v.Slice(func(_s []string) (_schemas []v.Schema) {
+			for _, _elem := range _s {
+				_schemas = append(_schemas, v.Value(_elem, verr.Leaf(verr.Info{Validator: "uuid"}, customvalidator.UUID())))
+			}
+			return
+		})),
+	}
+
+	if err := v.Validate(schema); err != nil {
+		return nil, mw.wrap(err)
+	}
+
+	return mw.next.GetMessages(ctx, userID, messageIDs)
+}
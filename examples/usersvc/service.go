@@ -2,29 +2,29 @@ package usersvc
 
 import (
 	"context"
-	"regexp"
-
-	v "github.com/RussellLuo/validating/v3"
-	"github.com/RussellLuo/vext"
 )
 
+//go:generate protogo validate-struct ./service.go User
 //go:generate protogo validate ./service.go Service
 
 type User struct {
-	Name  string
-	Age   int
-	Email string
+	// @schema: len(0, 10) && match(`^\w+$`)
+	Name string `json:"name"`
+	// @schema: xrange(0, 100)
+	Age int `json:"age"`
+	// @schema: email
+	Email string `json:"email"`
+}
+
+type Address struct {
+	City string
+	Zip  string
 }
 
-func (u User) Schema() v.Schema {
-	return v.Schema{
-		v.F("name", u.Name): v.All(
-			v.LenString(0, 10),
-			v.Match(regexp.MustCompile(`^\w+$`)),
-		),
-		v.F("age", u.Age):     v.Range(0, 100),
-		v.F("email", u.Email): vext.Email(),
-	}
+type Profile struct {
+	Nickname string
+	Bio      *string
+	Address  *Address
 }
 
 type Service interface {
@@ -32,7 +32,20 @@ type Service interface {
 	//
 	// @schema:
 	//   user: _
+	//
+	// @returns:
+	//   result: _
 	CreateUser(ctx context.Context, user User) (result User, err error)
+
+	// UpdateProfile updates the profile of the specified user.
+	//
+	// @schema:
+	//   name: nonzero
+	//   profile: required
+	//   profile.Nickname: len(1, 10)
+	//   profile.Bio: optional && runecnt(0, 20)
+	//   profile.Address.Zip: match(`^\d{5}$`).code("USR_001")
+	UpdateProfile(ctx context.Context, name string, profile *Profile) (err error)
 }
 
 type UserSvc struct{}
@@ -40,3 +53,7 @@ type UserSvc struct{}
 func (us UserSvc) CreateUser(ctx context.Context, user User) (result User, err error) {
 	return user, nil
 }
+
+func (us UserSvc) UpdateProfile(ctx context.Context, name string, profile *Profile) (err error) {
+	return nil
+}
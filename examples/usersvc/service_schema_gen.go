@@ -0,0 +1,20 @@
+// Code generated by validate; DO NOT EDIT.
+// github.com/protogodev/validate
+
+package usersvc
+
+import (
+	"regexp"
+
+	v "github.com/RussellLuo/validating/v3"
+	vext "github.com/RussellLuo/vext"
+	"github.com/protogodev/validate/verr"
+)
+
+func (u User) Schema() v.Schema {
+	return v.Schema{
+		v.F("name", u.Name):   v.All(verr.Leaf(verr.Info{Validator: "len", Args: []string{"0", "10"}}, v.LenString(0, 10)), verr.Leaf(verr.Info{Validator: "match", Args: []string{"^\\w+$"}}, v.Match(regexp.MustCompile(`^\w+$`)))),
+		v.F("age", u.Age):     verr.Leaf(verr.Info{Validator: "xrange", Args: []string{"0", "100"}}, v.Range[int](0, 100)),
+		v.F("email", u.Email): verr.Leaf(verr.Info{Validator: "email"}, vext.Email()),
+	}
+}
@@ -2,9 +2,14 @@ package usersvc_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 
+	v "github.com/RussellLuo/validating/v3"
 	"github.com/protogodev/validate/examples/usersvc"
+	"github.com/protogodev/validate/verr"
 )
 
 func Example() {
@@ -23,9 +28,54 @@ func Example() {
 		Age:   -1,
 		Email: "tracey#example.com",
 	})
-	fmt.Printf("created: %+v, err: %v\n", created, err)
+	fmt.Printf("created: %+v, err: %s\n", created, errString(err))
+
+	err = svc.UpdateProfile(context.Background(), "Tracey", &usersvc.Profile{
+		Nickname: "Tracey",
+	})
+	fmt.Printf("err: %v\n", err)
+
+	err = svc.UpdateProfile(context.Background(), "Tracey", &usersvc.Profile{
+		Nickname: "Tracey",
+		Address:  &usersvc.Address{City: "Paris", Zip: "750"},
+	})
+	fmt.Printf("err: %v\n", err)
+
+	details, _ := json.Marshal(verr.Details(err))
+	fmt.Printf("details: %s\n", details)
+
+	err = svc.UpdateProfile(context.Background(), "Tracey", nil)
+	fmt.Printf("err: %v\n", err)
+
+	bio := "A very long biography of Tracey"
+	err = svc.UpdateProfile(context.Background(), "Tracey", &usersvc.Profile{
+		Nickname: "Tracey",
+		Bio:      &bio,
+	})
+	fmt.Printf("err: %v\n", err)
 
 	// Output:
 	// created: {Name:Tracey Age:10 Email:tracey@example.com}, err: <nil>
-	// created: {Name: Age:0 Email:}, err: user.name: INVALID(does not match the given regular expression), user.age: INVALID(is not between the given range), user.email: INVALID(invalid email)
+	// created: {Name: Age:0 Email:}, err: user.age: INVALID(is not between the given range), user.email: INVALID(invalid email), user.name: INVALID(does not match the given regular expression)
+	// err: <nil>
+	// err: profile.Address.Zip: INVALID(does not match the given regular expression)
+	// details: [{"field":"profile.Address.Zip","kind":"INVALID","validator":"match","args":["^\\d{5}$"],"message":"does not match the given regular expression","code":"USR_001"}]
+	// err: profile: INVALID(is required)
+	// err: profile.Bio: INVALID(the number of runes is not between the given range)
+}
+
+// errString returns the string of err with the field errors sorted, since
+// the validation order of the fields in a schema is not deterministic.
+func errString(err error) string {
+	errs, ok := err.(v.Errors)
+	if !ok {
+		return fmt.Sprint(err)
+	}
+
+	var strs []string
+	for _, e := range errs {
+		strs = append(strs, e.Error())
+	}
+	sort.Strings(strs)
+	return strings.Join(strs, ", ")
 }
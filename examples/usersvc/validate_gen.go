@@ -5,25 +5,36 @@ package usersvc
 
 import (
 	"context"
+	"regexp"
 
 	v "github.com/RussellLuo/validating/v3"
+	"github.com/protogodev/validate/verr"
 )
 
 func ValidateMiddleware(wrap func(error) error) func(Service) Service {
+	return ValidateMiddlewareWithReturns(wrap, nil)
+}
+
+func ValidateMiddlewareWithReturns(wrap, wrapReturns func(error) error) func(Service) Service {
 	return func(next Service) Service {
 		if wrap == nil {
 			wrap = func(err error) error { return err }
 		}
+		if wrapReturns == nil {
+			wrapReturns = func(err error) error { return err }
+		}
 		return validateMiddleware{
-			next: next,
-			wrap: wrap,
+			next:        next,
+			wrap:        wrap,
+			wrapReturns: wrapReturns,
 		}
 	}
 }
 
 type validateMiddleware struct {
-	next Service
-	wrap func(error) error
+	next        Service
+	wrap        func(error) error
+	wrapReturns func(error) error
 }
 
 func (mw validateMiddleware) CreateUser(ctx context.Context, user User) (User, error) {
@@ -35,5 +46,45 @@ func (mw validateMiddleware) CreateUser(ctx context.Context, user User) (User, e
 		return User{}, mw.wrap(err)
 	}
 
-	return mw.next.CreateUser(ctx, user)
+	result, err := mw.next.CreateUser(ctx, user)
+	if err != nil {
+		return result, err
+	}
+
+	returnsSchema := v.Schema{
+		v.F("result", result): result.Schema(),
+	}
+
+	if err := v.Validate(returnsSchema); err != nil {
+		return User{}, mw.wrapReturns(err)
+	}
+
+	return result, err
+}
+
+func (mw validateMiddleware) UpdateProfile(ctx context.Context, name string, profile *Profile) error {
+	schema := v.Schema{
+		v.F("name", name):       verr.Leaf(verr.Info{Validator: "nonzero"}, v.Nonzero[string]()),
+		v.F("profile", profile): verr.Leaf(verr.Info{Validator: "required"}, v.Nonzero[*Profile]().Msg("is required")),
+	}
+	if profile != nil {
+		schema[v.F("profile.Nickname", profile.Nickname)] = verr.Leaf(verr.Info{Validator: "len", Args: []string{"1", "10"}}, v.LenString(1, 10))
+	}
+	if profile != nil {
+		schema[v.F("profile.Bio", profile.Bio)] = v.Nested(func(_ptr *string) v.Validator {
+			if _ptr == nil {
+				return v.All()
+			}
+			return v.Value(*_ptr, verr.Leaf(verr.Info{Validator: "runecnt", Args: []string{"0", "20"}}, v.RuneCount(0, 20)))
+		})
+	}
+	if profile != nil && profile.Address != nil {
+		schema[v.F("profile.Address.Zip", profile.Address.Zip)] = verr.Leaf(verr.Info{Validator: "match", Args: []string{"^\\d{5}$"}, Code: "USR_001"}, v.Match(regexp.MustCompile(`^\d{5}$`)))
+	}
+
+	if err := v.Validate(schema); err != nil {
+		return mw.wrap(err)
+	}
+
+	return mw.next.UpdateProfile(ctx, name, profile)
 }
@@ -0,0 +1,95 @@
+package validate
+
+import (
+	"errors"
+	"go/types"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/protogodev/protogo/parser/ifacetool"
+	"github.com/protogodev/validate/runtime"
+)
+
+func TestMethodExamples(t *testing.T) {
+	pkg := types.NewPackage("example.com/user", "user")
+	user := types.NewNamed(types.NewTypeName(0, pkg, "User", nil), types.NewStruct(nil, nil), nil)
+	method := &ifacetool.Method{
+		Name: "Create",
+		Params: []*ifacetool.Param{
+			{Name: "ctx", TypeString: "context.Context"},
+			{Name: "name", TypeString: "string", Type: types.Typ[types.String]},
+			{Name: "answer", TypeString: "*string", Type: types.NewPointer(types.Typ[types.String])},
+			{Name: "user", TypeString: "user.User", Type: user},
+		},
+	}
+
+	tests := []struct {
+		name              string
+		in                map[string][]Option
+		want              map[string]interface{}
+		wantErrStr        string
+		wantUnsatisfiable bool
+	}{
+		{
+			name: "ok",
+			in: map[string][]Option{
+				"schema": {
+					{K: "name", V: "len(3, 10) && match(`^[a-z]+$`)"},
+					{K: "answer", V: `required && in("Y", "N")`},
+				},
+			},
+			want: map[string]interface{}{
+				"name":   "aaa",
+				"answer": func() *string { s := "Y"; return &s }(),
+			},
+		},
+		{
+			name: "no schema",
+			in:   map[string][]Option{},
+		},
+		{
+			name: "assert",
+			in: map[string][]Option{
+				"schema": {{K: "name", V: "len(3, 10)"}},
+				"assert": {{K: "name", V: `name != "abc"`}},
+			},
+			wantErrStr: "cannot synthesize the params satisfying @assert",
+		},
+		{
+			name: "unsupported type",
+			in: map[string][]Option{
+				"schema": {{K: "user", V: "_"}},
+			},
+			wantErrStr: "cannot synthesize user (only the params of the basic types, times, durations, or the slices or pointers of them are supported)",
+		},
+		{
+			name: "unsatisfiable",
+			in: map[string][]Option{
+				"schema": {{K: "name", V: "len(3, 1)"}},
+			},
+			wantErrStr:        `param "name": unsatisfiable: no length of ` + "`len`" + ` is within [3, 1]`,
+			wantUnsatisfiable: true,
+		},
+	}
+
+	r := runtime.New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := methodExamples(r, method, tt.in)
+			var gotErrStr string
+			if err != nil {
+				gotErrStr = err.Error()
+			}
+			if gotErrStr != tt.wantErrStr {
+				t.Fatalf("Err: got (%#v), want (%#v)", gotErrStr, tt.wantErrStr)
+			}
+			if errors.Is(err, runtime.ErrUnsatisfiable) != tt.wantUnsatisfiable {
+				t.Errorf("Unsatisfiable: got (%v), want (%v)", !tt.wantUnsatisfiable, tt.wantUnsatisfiable)
+			}
+			if !cmp.Equal(got, tt.want) {
+				diff := cmp.Diff(tt.want, got)
+				t.Errorf("Want - Got: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package expr
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strconv"
+
+	"github.com/protogodev/validate/decl"
+)
+
+// AssertValidator is an expression that represents an assertion, which is
+// a boolean Go expression across the variables in scope (e.g. `start < end`).
+type AssertValidator struct {
+	Qualifier string
+	Expr      string
+	Msg       string
+	Code      string
+
+	Param Param
+}
+
+func (v *AssertValidator) Bind(param Param, decls map[string][]*decl.Validator) error {
+	v.Param = param
+
+	pkg := types.NewPackage("assert", "assert")
+	for name, typ := range param.Scope {
+		pkg.Scope().Insert(types.NewVar(token.NoPos, pkg, name, typ))
+	}
+
+	e, err := parser.ParseExpr(v.Expr)
+	if err != nil {
+		return err
+	}
+
+	// Make the referenced packages (e.g. `time` in `end.Before(time.Now())`)
+	// available, preferring the ones reachable from the types in scope, which
+	// keeps their types identical.
+	pkgs := scopePackages(param.Scope)
+	for _, name := range qualifiers(e) {
+		if _, ok := param.Scope[name]; ok {
+			continue
+		}
+		path, ok := param.Imports[name]
+		if !ok {
+			continue
+		}
+		imported := pkgs[path]
+		if imported == nil {
+			if param.Importer == nil {
+				return nil
+			}
+			if imported, err = param.Importer.Import(path); err != nil {
+				return fmt.Errorf("cannot import %q: %v", path, err)
+			}
+		}
+		pkg.Scope().Insert(types.NewPkgName(token.NoPos, pkg, name, imported))
+	}
+
+	info := &types.Info{Types: make(map[ast.Expr]types.TypeAndValue)}
+	if err := types.CheckExpr(token.NewFileSet(), pkg, token.NoPos, e, info); err != nil {
+		if terr, ok := err.(types.Error); ok {
+			return fmt.Errorf("1:%d %s", terr.Pos, terr.Msg)
+		}
+		return err
+	}
+
+	typ := info.Types[e].Type
+	if b, ok := typ.Underlying().(*types.Basic); !ok || b.Info()&types.IsBoolean == 0 {
+		return fmt.Errorf("non-boolean assertion %s (type %s)", v.Expr, types.TypeString(typ, param.Qualifier))
+	}
+
+	return nil
+}
+
+func (v *AssertValidator) ExprString() string {
+	msg := v.Msg
+	if msg == "" {
+		msg = strconv.Quote("must satisfy " + v.Expr)
+	}
+	validator := fmt.Sprintf("%s.Is(func(%s) bool { return %s }).Msg(%s)", v.Qualifier, v.Param.TypeString(), v.Expr, msg)
+
+	info := verrInfo{Validator: "assert", Args: []string{strconv.Quote(v.Expr)}, Code: v.Code}
+	return info.Wrap(validator)
+}
+
+// ParseAssert parses the assertion s, which may be followed by a custom error
+// message and a custom error code (e.g. `(start < end).msg("must be after start")`).
+func ParseAssert(s string) (Validator, error) {
+	e, err := parser.ParseExpr(s)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &AssertValidator{
+		Qualifier: DefaultQualifier,
+		Expr:      s,
+	}
+
+	// Only the trailing calls of `msg` and `code` are the modifiers, while
+	// the other calls belong to the assertion (e.g. `end.After(start)`).
+	p := Parser{S: s}
+	mods := make(map[string]string)
+	x := e
+	for {
+		call, ok := x.(*ast.CallExpr)
+		if !ok {
+			break
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "msg" && sel.Sel.Name != "code" {
+			break
+		}
+		if err := p.parseModifier(call, sel, mods); err != nil {
+			return nil, err
+		}
+		x = sel.X
+	}
+
+	if len(mods) > 0 {
+		if paren, ok := x.(*ast.ParenExpr); ok {
+			x = paren.X
+		}
+		v.Expr = p.string(x)
+		v.Msg, v.Code = mods["msg"], mods["code"]
+	}
+	return v, nil
+}
+
+// qualifiers returns the identifiers, which are possibly the qualifiers of
+// packages in e (e.g. `time` in `time.Now()`).
+func qualifiers(e ast.Expr) (names []string) {
+	ast.Inspect(e, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok {
+				names = append(names, ident.Name)
+			}
+		}
+		return true
+	})
+	return
+}
+
+// scopePackages returns the packages of the named types, which are reachable
+// from the types in scope, keyed by the import paths.
+func scopePackages(scope map[string]types.Type) map[string]*types.Package {
+	pkgs := make(map[string]*types.Package)
+	seen := make(map[types.Type]bool)
+	var walk func(typ types.Type)
+	walk = func(typ types.Type) {
+		if typ == nil || seen[typ] {
+			return
+		}
+		seen[typ] = true
+
+		switch t := typ.(type) {
+		case *types.Named:
+			if pkg := t.Obj().Pkg(); pkg != nil {
+				pkgs[pkg.Path()] = pkg
+			}
+			for i := 0; i < t.TypeArgs().Len(); i++ {
+				walk(t.TypeArgs().At(i))
+			}
+		case *types.Pointer:
+			walk(t.Elem())
+		case *types.Slice:
+			walk(t.Elem())
+		case *types.Array:
+			walk(t.Elem())
+		case *types.Map:
+			walk(t.Key())
+			walk(t.Elem())
+		case *types.Chan:
+			walk(t.Elem())
+		}
+	}
+	for _, typ := range scope {
+		walk(typ)
+	}
+	return pkgs
+}
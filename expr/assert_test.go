@@ -0,0 +1,201 @@
+package expr_test
+
+import (
+	"go/importer"
+	"go/types"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/protogodev/validate/expr"
+)
+
+func TestParseAssert(t *testing.T) {
+	scope := map[string]types.Type{
+		"start": types.Typ[types.Int],
+		"end":   types.Typ[types.Int],
+		"name":  types.Typ[types.String],
+	}
+
+	timePkg, err := importer.Default().Import("time")
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+	timeType := timePkg.Scope().Lookup("Time").Type()
+	timeScope := map[string]types.Type{
+		"start": timeType,
+		"end":   timeType,
+		"name":  types.Typ[types.String],
+	}
+	qualifier := func(p *types.Package) string { return p.Name() }
+
+	tests := []struct {
+		name           string
+		inStr          string
+		inParam        expr.Param
+		wantExprString string
+		wantErrStr     string
+	}{
+		{
+			name:  "default msg",
+			inStr: "start < end",
+			inParam: expr.Param{
+				Name:  "end",
+				Type:  types.Typ[types.Int],
+				Scope: scope,
+			},
+			wantExprString: `verr.Leaf(verr.Info{Validator: "assert", Args: []string{"start < end"}}, v.Is(func(int) bool { return start < end }).Msg("must satisfy start < end"))`,
+		},
+		{
+			name:  "custom msg",
+			inStr: `(end-start <= 10 && name != "").msg("bad range")`,
+			inParam: expr.Param{
+				Name:  "end",
+				Type:  types.Typ[types.Int],
+				Scope: scope,
+			},
+			wantExprString: `verr.Leaf(verr.Info{Validator: "assert", Args: []string{"end-start <= 10 && name != \"\""}}, v.Is(func(int) bool { return end-start <= 10 && name != "" }).Msg("bad range"))`,
+		},
+		{
+			name:  "custom code",
+			inStr: `(start < end).code("RANGE").msg("bad range")`,
+			inParam: expr.Param{
+				Name:  "end",
+				Type:  types.Typ[types.Int],
+				Scope: scope,
+			},
+			wantExprString: `verr.Leaf(verr.Info{Validator: "assert", Args: []string{"start < end"}, Code: "RANGE"}, v.Is(func(int) bool { return start < end }).Msg("bad range"))`,
+		},
+		{
+			name:  "non-boolean",
+			inStr: "end - start",
+			inParam: expr.Param{
+				Name:  "end",
+				Type:  types.Typ[types.Int],
+				Scope: scope,
+			},
+			wantErrStr: "non-boolean assertion end - start (type int)",
+		},
+		{
+			name:  "mismatched types",
+			inStr: "start < name",
+			inParam: expr.Param{
+				Name:  "end",
+				Type:  types.Typ[types.Int],
+				Scope: scope,
+			},
+			wantErrStr: "1:9 invalid operation: start < name (mismatched types int and string)",
+		},
+		{
+			name:  "method call",
+			inStr: "end.After(start)",
+			inParam: expr.Param{
+				Name:      "end",
+				Type:      timeType,
+				Qualifier: qualifier,
+				Scope:     timeScope,
+			},
+			wantExprString: `verr.Leaf(verr.Info{Validator: "assert", Args: []string{"end.After(start)"}}, v.Is(func(time.Time) bool { return end.After(start) }).Msg("must satisfy end.After(start)"))`,
+		},
+		{
+			name:  "method call with modifiers",
+			inStr: `end.After(start).msg("bad range").code("RANGE")`,
+			inParam: expr.Param{
+				Name:      "end",
+				Type:      timeType,
+				Qualifier: qualifier,
+				Scope:     timeScope,
+			},
+			wantExprString: `verr.Leaf(verr.Info{Validator: "assert", Args: []string{"end.After(start)"}, Code: "RANGE"}, v.Is(func(time.Time) bool { return end.After(start) }).Msg("bad range"))`,
+		},
+		{
+			name:  "non-boolean method call",
+			inStr: "end.Sub(start)",
+			inParam: expr.Param{
+				Name:      "end",
+				Type:      timeType,
+				Qualifier: qualifier,
+				Scope:     timeScope,
+			},
+			wantErrStr: "non-boolean assertion end.Sub(start) (type time.Duration)",
+		},
+		{
+			name:  "package in scope",
+			inStr: "end.Sub(start) <= time.Hour",
+			inParam: expr.Param{
+				Name:      "end",
+				Type:      timeType,
+				Qualifier: qualifier,
+				Scope:     timeScope,
+				Imports:   map[string]string{"time": "time"},
+			},
+			wantExprString: `verr.Leaf(verr.Info{Validator: "assert", Args: []string{"end.Sub(start) <= time.Hour"}}, v.Is(func(time.Time) bool { return end.Sub(start) <= time.Hour }).Msg("must satisfy end.Sub(start) <= time.Hour"))`,
+		},
+		{
+			name:  "imported package",
+			inStr: "len(name) <= utf8.UTFMax",
+			inParam: expr.Param{
+				Name:     "name",
+				Type:     types.Typ[types.String],
+				Scope:    scope,
+				Imports:  map[string]string{"utf8": "unicode/utf8"},
+				Importer: importer.Default(),
+			},
+			wantExprString: `verr.Leaf(verr.Info{Validator: "assert", Args: []string{"len(name) <= utf8.UTFMax"}}, v.Is(func(string) bool { return len(name) <= utf8.UTFMax }).Msg("must satisfy len(name) <= utf8.UTFMax"))`,
+		},
+		{
+			name:  "imported package mistyped",
+			inStr: "name <= utf8.UTFMax",
+			inParam: expr.Param{
+				Name:     "name",
+				Type:     types.Typ[types.String],
+				Scope:    scope,
+				Imports:  map[string]string{"utf8": "unicode/utf8"},
+				Importer: importer.Default(),
+			},
+			wantErrStr: "1:9 invalid operation: name <= utf8.UTFMax (mismatched types string and untyped int)",
+		},
+		{
+			name:  "not imported",
+			inStr: `strings.HasPrefix(name, "a")`,
+			inParam: expr.Param{
+				Name:     "name",
+				Type:     types.Typ[types.String],
+				Scope:    scope,
+				Importer: importer.Default(),
+			},
+			wantErrStr: "1:1 undefined: strings",
+		},
+		{
+			name:  "undefined",
+			inStr: "begin < end",
+			inParam: expr.Param{
+				Name:  "end",
+				Type:  types.Typ[types.Int],
+				Scope: scope,
+			},
+			wantErrStr: "1:1 undefined: begin",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator, err1 := expr.ParseAssert(tt.inStr)
+
+			var err2 error
+			if validator != nil {
+				err2 = validator.Bind(tt.inParam, nil)
+			}
+			cmpError(t, err1, err2, tt.wantErrStr)
+
+			var gotExprString string
+			if err1 == nil && err2 == nil {
+				gotExprString = validator.ExprString()
+			}
+
+			if !cmp.Equal(gotExprString, tt.wantExprString) {
+				diff := cmp.Diff(gotExprString, tt.wantExprString)
+				t.Errorf("Want - Got: %s", diff)
+			}
+		})
+	}
+}
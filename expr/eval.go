@@ -0,0 +1,68 @@
+package expr
+
+import (
+	"go/constant"
+	"go/token"
+	"go/types"
+	"math"
+	"time"
+)
+
+// constPkg is a package, in whose scope the package time only declares the
+// duration units (e.g. `time.Hour`), and the package math only declares the
+// integer limits (e.g. `math.MaxInt`).
+var constPkg = func() *types.Package {
+	timePkg := types.NewPackage("time", "time")
+	duration := types.NewNamed(types.NewTypeName(token.NoPos, timePkg, "Duration", nil), types.Typ[types.Int64], nil)
+	timePkg.Scope().Insert(duration.Obj())
+	for name, unit := range map[string]time.Duration{
+		"Nanosecond":  time.Nanosecond,
+		"Microsecond": time.Microsecond,
+		"Millisecond": time.Millisecond,
+		"Second":      time.Second,
+		"Minute":      time.Minute,
+		"Hour":        time.Hour,
+	} {
+		timePkg.Scope().Insert(types.NewConst(token.NoPos, timePkg, name, duration, constant.MakeInt64(int64(unit))))
+	}
+	timePkg.MarkComplete()
+
+	mathPkg := types.NewPackage("math", "math")
+	for name, value := range map[string]constant.Value{
+		"MaxInt":    constant.MakeInt64(math.MaxInt),
+		"MinInt":    constant.MakeInt64(math.MinInt),
+		"MaxInt8":   constant.MakeInt64(math.MaxInt8),
+		"MinInt8":   constant.MakeInt64(math.MinInt8),
+		"MaxInt16":  constant.MakeInt64(math.MaxInt16),
+		"MinInt16":  constant.MakeInt64(math.MinInt16),
+		"MaxInt32":  constant.MakeInt64(math.MaxInt32),
+		"MinInt32":  constant.MakeInt64(math.MinInt32),
+		"MaxInt64":  constant.MakeInt64(math.MaxInt64),
+		"MinInt64":  constant.MakeInt64(math.MinInt64),
+		"MaxUint":   constant.MakeUint64(math.MaxUint),
+		"MaxUint8":  constant.MakeUint64(math.MaxUint8),
+		"MaxUint16": constant.MakeUint64(math.MaxUint16),
+		"MaxUint32": constant.MakeUint64(math.MaxUint32),
+		"MaxUint64": constant.MakeUint64(math.MaxUint64),
+	} {
+		mathPkg.Scope().Insert(types.NewConst(token.NoPos, mathPkg, name, types.Typ[types.UntypedInt], value))
+	}
+	mathPkg.MarkComplete()
+
+	pkg := types.NewPackage("args", "args")
+	pkg.Scope().Insert(types.NewPkgName(token.NoPos, pkg, "time", timePkg))
+	pkg.Scope().Insert(types.NewPkgName(token.NoPos, pkg, "math", mathPkg))
+	return pkg
+}()
+
+// EvalConst evaluates the constant expression s (e.g. `1 << 20`), which
+// involves no identifiers other than the duration units (e.g.
+// `24 * time.Hour`) and the integer limits (e.g. `math.MaxInt`). It reports
+// false if s is not such an expression.
+func EvalConst(s string) (constant.Value, bool) {
+	tv, err := types.Eval(token.NewFileSet(), constPkg, token.NoPos, s)
+	if err != nil || tv.Value == nil {
+		return nil, false
+	}
+	return tv.Value, true
+}
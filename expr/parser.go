@@ -1,23 +1,55 @@
 package expr
 
 import (
+	"errors"
 	"fmt"
 	"go/ast"
 	"go/parser"
+	"go/scanner"
 	"go/token"
 	"go/types"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/protogodev/validate/decl"
+	"github.com/protogodev/validate/verr"
 )
 
 const (
 	DefaultQualifier = "v"
+	VerrQualifier    = "verr"
 )
 
 type Param struct {
 	Name string
 	Type types.Type
+
+	// Qualifier controls how package-level objects are qualified in the
+	// type string. If nil, the full package path is used.
+	Qualifier types.Qualifier
+
+	// Scope holds the variables (e.g. the other params of the method),
+	// which can be referenced by the validator arguments.
+	Scope map[string]types.Type
+
+	// Ctx is the name of the context.Context variable, which will be passed
+	// to the context-aware validators. It is empty if no context is available.
+	Ctx string
+
+	// Imports maps the qualifiers to the import paths of the packages (e.g.
+	// the ones imported by the source file), which can be referenced by the
+	// assertions.
+	Imports map[string]string
+	// Importer imports the packages in Imports on demand, which are used to
+	// type-check the assertions. If nil, the assertions referencing them are
+	// left to the Go compiler.
+	Importer types.Importer
+}
+
+// TypeString returns the string representation of the param type.
+func (p Param) TypeString() string {
+	return types.TypeString(p.Type, p.Qualifier)
 }
 
 type Validator interface {
@@ -32,6 +64,21 @@ type LeafValidator struct {
 	Name string
 	Args []string
 	Msg  string
+	Code string
+
+	// ArgNames holds the names of the named arguments (e.g. `max` in
+	// `len(max: 10)`), and an empty string for each positional argument.
+	// The named arguments will be resolved into positional ones by Bind.
+	ArgNames []string
+	// ArgPos holds the positions of Args in the expression string, which
+	// are used to report the argument errors. The omitted arguments, which
+	// are filled with the default values, have no positions.
+	ArgPos []token.Pos
+
+	// literalArgs holds the arguments as written (e.g. `"24h"`), if any of
+	// them has been converted into a Go expression (e.g. `24 * time.Hour`).
+	// They are carried by the structured errors instead of Args.
+	literalArgs []string
 
 	Param Param
 	Decls []*decl.Validator
@@ -45,17 +92,92 @@ func (v *LeafValidator) Bind(param Param, decls map[string][]*decl.Validator) er
 }
 
 func (v *LeafValidator) ExprString() string {
+	// Special case for validator `_`.
+	if v.Name == "_" {
+		return v.Param.Name + ".Schema()"
+	}
+
+	return v.info().Wrap(v.exprString())
+}
+
+func (v *LeafValidator) info() verrInfo {
+	args := v.Args
+	if v.literalArgs != nil {
+		args = v.literalArgs
+	}
+	return verrInfo{Validator: v.Name, Args: args, Code: v.Code}
+}
+
+// Info returns the information about the validator, which will be carried
+// by the structured errors.
+func (v *LeafValidator) Info() verr.Info {
+	return v.info().Info()
+}
+
+func (v *LeafValidator) exprString() string {
 	qualifiedName := v.buildQualifiedName()
 
 	args := strings.Join(v.Args, ", ")
+	if d := v.MatchedDecl(); d != nil && d.Ctx {
+		// Pass the context to the context-aware validator.
+		args = strings.Join(append([]string{v.Param.Ctx}, v.Args...), ", ")
+	}
 	if v.Name == "match" {
 		args = fmt.Sprintf("regexp.MustCompile(%s)", args)
 	}
 
-	if v.Msg == "" {
+	msg := v.Message()
+	if msg == "" {
 		return fmt.Sprintf("%s(%s)", qualifiedName, args)
 	}
-	return fmt.Sprintf("%s(%s).Msg(%s)", qualifiedName, args, v.Msg)
+	return fmt.Sprintf("%s(%s).Msg(%s)", qualifiedName, args, msg)
+}
+
+// Message returns the custom error message as a Go string literal, which is
+// either specified by `.msg(...)` or defaulted for the arguments referencing
+// other variables. It returns an empty string if there is none.
+func (v *LeafValidator) Message() string {
+	if v.Msg != "" {
+		return v.Msg
+	}
+	return v.refMsg()
+}
+
+// refMsgFormats are the formats of the default error messages for the
+// comparison validators, whose arguments reference other variables.
+var refMsgFormats = map[string]string{
+	"eq":     "must equal %s",
+	"ne":     "must not equal %s",
+	"gt":     "must be greater than %s",
+	"gte":    "must be greater than or equal to %s",
+	"lt":     "must be less than %s",
+	"lte":    "must be less than or equal to %s",
+	"xrange": "must be between %s and %s",
+}
+
+// refMsg returns a default error message, which mentions the referenced
+// variables, if any.
+func (v *LeafValidator) refMsg() string {
+	format, ok := refMsgFormats[v.Name]
+	if !ok || len(v.refs()) == 0 {
+		return ""
+	}
+
+	args := make([]interface{}, len(v.Args))
+	for i, arg := range v.Args {
+		args[i] = arg
+	}
+	return strconv.Quote(fmt.Sprintf(format, args...))
+}
+
+// refs returns the arguments that reference the variables in scope.
+func (v *LeafValidator) refs() (refs []string) {
+	for _, arg := range v.Args {
+		if _, ok := v.Param.Scope[arg]; ok {
+			refs = append(refs, arg)
+		}
+	}
+	return
 }
 
 func (v *LeafValidator) validate() error {
@@ -86,32 +208,267 @@ func (v *LeafValidator) validate() error {
 
 	// Apply the argument number constraint from the above matched declaration.
 	d := v.Decls[idx]
+	if err := v.resolveArgs(d); err != nil {
+		return err
+	}
 	if !d.ArgNum.Contain(len(v.Args)) {
 		return fmt.Errorf("wrong number of arguments for validator %q", v.Name)
 	}
 
+	if d.Ctx && v.Param.Ctx == "" {
+		return fmt.Errorf("validator `%s` requires a context.Context, which is not available here", v.Name)
+	}
+
+	if err := v.convertTimeArgs(d); err != nil {
+		return err
+	}
+	return v.checkArgs(d)
+}
+
+// resolveArgs reorders the named arguments and fills the omitted trailing
+// arguments with the default values, per the declaration d.
+func (v *LeafValidator) resolveArgs(d *decl.Validator) error {
+	// The number of positional arguments, which precede the named ones.
+	n := len(v.Args)
+	named := make(map[string]int)
+	for i, name := range v.ArgNames {
+		if name == "" {
+			continue
+		}
+		if len(named) == 0 {
+			n = i
+		}
+		named[name] = i
+	}
+	if len(d.Args) == 0 {
+		if len(named) > 0 {
+			return v.argError(n, "validator `%s` does not accept named arguments", v.Name)
+		}
+		return nil
+	}
+
+	var args []string
+	var argPos []token.Pos
+	for i, arg := range d.Args {
+		if arg.Variadic {
+			if idx, ok := named[arg.Name]; ok && arg.Name != "" {
+				return v.argError(idx, "cannot pass variadic argument %q of validator `%s` by name", arg.Name, v.Name)
+			}
+			// All the remaining positional arguments.
+			if i < n {
+				args = append(args, v.Args[i:n]...)
+				argPos = append(argPos, v.argPos(i, n)...)
+			}
+			break
+		}
+
+		idx, ok := named[arg.Name]
+		switch {
+		case i < n:
+			if ok {
+				return v.argError(idx, "argument %q of validator `%s` is given twice", arg.Name, v.Name)
+			}
+			args = append(args, v.Args[i])
+			argPos = append(argPos, v.argPos(i, i+1)...)
+		case ok:
+			args = append(args, v.Args[idx])
+			argPos = append(argPos, v.argPos(idx, idx+1)...)
+		case arg.Default != "":
+			args = append(args, arg.Default)
+			argPos = append(argPos, token.NoPos)
+		case len(named) > 0:
+			return fmt.Errorf("missing argument %q for validator `%s`", arg.Name, v.Name)
+		default:
+			// Leave the missing positional arguments to the argument
+			// number constraint.
+			return nil
+		}
+		delete(named, arg.Name)
+	}
+	for _, idx := range named {
+		return v.argError(idx, "unknown argument %q for validator `%s`", v.ArgNames[idx], v.Name)
+	}
+	if !d.Args[len(d.Args)-1].Variadic && n > len(d.Args) {
+		// Leave the extra positional arguments to the argument number constraint.
+		return nil
+	}
+
+	v.Args, v.ArgNames, v.ArgPos = args, nil, argPos
 	return nil
 }
 
+// argPos returns the positions of the arguments in [i, j), if known.
+func (v *LeafValidator) argPos(i, j int) []token.Pos {
+	pos := make([]token.Pos, j-i)
+	if j <= len(v.ArgPos) {
+		copy(pos, v.ArgPos[i:j])
+	}
+	return pos
+}
+
+// argError returns an error, which is prefixed with the position of the
+// i-th argument, if known.
+func (v *LeafValidator) argError(i int, format string, a ...interface{}) error {
+	msg := fmt.Sprintf(format, a...)
+	if i < len(v.ArgPos) && v.ArgPos[i].IsValid() {
+		return fmt.Errorf("1:%d %s", v.ArgPos[i], msg)
+	}
+	return errors.New(msg)
+}
+
+// Defaulted reports whether the i-th argument is omitted, and is filled with
+// the default value.
+func (v *LeafValidator) Defaulted(i int) bool {
+	return i < len(v.ArgPos) && !v.ArgPos[i].IsValid()
+}
+
+// argKindTypes maps the declared argument kinds to the types.
+var argKindTypes = map[string]types.Type{
+	"int":    types.Typ[types.Int],
+	"uint":   types.Typ[types.Uint],
+	"float":  types.Typ[types.Float64],
+	"string": types.Typ[types.String],
+	"bool":   types.Typ[types.Bool],
+}
+
+// argType returns the wanted type of the i-th argument per the declaration
+// d, or nil if unknown.
+func (v *LeafValidator) argType(d *decl.Validator, i int) types.Type {
+	if len(d.Args) == 0 {
+		// Only the number of arguments is declared, which are assumed to be
+		// the type arguments of the generic validators.
+		if d.IsGeneric {
+			return v.Param.Type
+		}
+		return nil
+	}
+
+	if i >= len(d.Args) {
+		i = len(d.Args) - 1 // The variadic argument.
+	}
+	switch kind := d.Args[i].Kind; kind {
+	case "T":
+		return v.Param.Type
+	case "time":
+		return v.Param.timeType("Time")
+	case "duration":
+		return v.Param.timeType("Duration")
+	default:
+		return argKindTypes[kind]
+	}
+}
+
+// reArgErrDetail matches the detail (e.g. `(overflows)`) of the errors
+// reported by go/types for the invalid arguments.
+var reArgErrDetail = regexp.MustCompile(`\((overflows|truncated)\)$`)
+
+// checkArgs type-checks the arguments against the declaration d. Unless
+// declared otherwise, the arguments of the generic validators must be
+// assignable to the type of the param, which is the type argument.
+func (v *LeafValidator) checkArgs(d *decl.Validator) error {
+	pkg := types.NewPackage("args", "args")
+	for name, typ := range v.Param.Scope {
+		pkg.Scope().Insert(types.NewVar(token.NoPos, pkg, name, typ))
+	}
+
+	for i, arg := range v.Args {
+		want := v.argType(d, i)
+		if want == nil || v.Defaulted(i) {
+			// The argument type is unknown, or the default value is used.
+			continue
+		}
+
+		e, err := parser.ParseExpr(arg)
+		if err != nil {
+			return err
+		}
+		if !resolvable(e, pkg.Scope()) {
+			// The constants declared out of the scope (e.g. in the source
+			// package) cannot be checked here.
+			continue
+		}
+
+		var offset token.Pos
+		if i < len(v.ArgPos) {
+			offset = v.ArgPos[i] - 1
+		}
+
+		// A function accepting an argument of the wanted type, whose calls
+		// are used to check the assignability. Its name is not a valid
+		// identifier, which will never conflict with the variables in scope.
+		check := fmt.Sprintf("$check%d", i)
+		sig := types.NewSignatureType(nil, nil, nil, types.NewTuple(types.NewVar(token.NoPos, pkg, "", want)), nil, false)
+		pkg.Scope().Insert(types.NewVar(token.NoPos, pkg, check, sig))
+
+		info := &types.Info{Types: make(map[ast.Expr]types.TypeAndValue)}
+		if err := types.CheckExpr(token.NewFileSet(), pkg, token.NoPos, e, info); err != nil {
+			if terr, ok := err.(types.Error); ok {
+				return fmt.Errorf("1:%d %s", offset+terr.Pos, terr.Msg)
+			}
+			return err
+		}
+		typ := info.Types[e].Type
+
+		call := &ast.CallExpr{Fun: ast.NewIdent(check), Args: []ast.Expr{e}}
+		if err := types.CheckExpr(token.NewFileSet(), pkg, token.NoPos, call, nil); err != nil {
+			msg := fmt.Sprintf("1:%d cannot use %s (type %s) as %s in argument to validator `%s`",
+				offset+e.Pos(), arg, types.TypeString(typ, v.Param.Qualifier), types.TypeString(want, v.Param.Qualifier), v.Name)
+			if m := reArgErrDetail.FindStringSubmatch(err.Error()); m != nil {
+				msg += " " + m[0]
+			}
+			return errors.New(msg)
+		}
+	}
+
+	return nil
+}
+
+// resolvable reports whether all the identifiers in e can be resolved in
+// scope (or the universe scope).
+func resolvable(e ast.Expr, scope *types.Scope) bool {
+	ok := true
+	ast.Inspect(e, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			// A qualified identifier (e.g. `limits.MaxAge`).
+			ok = false
+		case *ast.Ident:
+			if _, obj := scope.LookupParent(n.Name, token.NoPos); obj == nil {
+				ok = false
+			}
+		}
+		return ok
+	})
+	return ok
+}
+
 func (v *LeafValidator) buildQualifiedName() string {
 	// Special case for validator `_`.
 	if v.Name == "_" {
 		return v.Param.Name + ".Schema"
 	}
 
+	d := v.MatchedDecl()
+	if d == nil {
+		return ""
+	}
+
+	// Return the qualified name of the first matched declaration.
+	name := d.Qualifier + "." + d.Name
+	if d.IsGeneric {
+		name += "[" + v.Param.TypeString() + "]"
+	}
+	return name
+}
+
+// MatchedDecl returns the first declaration, which matches the param type.
+func (v *LeafValidator) MatchedDecl() *decl.Validator {
 	for _, d := range v.Decls {
 		if d.AllowedTypes.Allow(v.Param.Type) {
-			// Return the qualified name of the first matched declaration.
-
-			name := d.Qualifier + "." + d.Name
-			if d.IsGeneric {
-				name += "[" + v.Param.Type.String() + "]"
-			}
-			return name
+			return d
 		}
 	}
-
-	return ""
+	return nil
 }
 
 // LogicValidator is an expression that represents a logic validator (i.e. `Not`, `And/All` or `Or/Any`).
@@ -154,14 +511,153 @@ func (v *LogicValidator) buildQualifiedName() string {
 	return ""
 }
 
+// ElemValidator is an expression that applies the inner validator to each
+// element (i.e. `each`), key (i.e. `keys`) or value (i.e. `values`) of the
+// param, which must be a slice, an array or a map.
+type ElemValidator struct {
+	Qualifier string
+	Name      string
+	Inner     Validator
+
+	Param Param
+}
+
+func (v *ElemValidator) Bind(param Param, decls map[string][]*decl.Validator) error {
+	v.Param = param
+
+	var elem Param
+	switch v.Name {
+	case "each":
+		if !decl.IsSlice(param.Type) && !decl.IsArray(param.Type) {
+			return fmt.Errorf("cannot use validator `%s` on type %T", v.Name, param.Type.Underlying())
+		}
+		elem = Param{
+			Name: "_elem",
+			Type: decl.CoreType(param.Type).(interface{ Elem() types.Type }).Elem(),
+		}
+	case "keys", "values":
+		if !decl.IsMap(param.Type) {
+			return fmt.Errorf("cannot use validator `%s` on type %T", v.Name, param.Type.Underlying())
+		}
+		m := decl.CoreType(param.Type).(*types.Map)
+		elem = Param{Name: "_key", Type: m.Key()}
+		if v.Name == "values" {
+			elem = Param{Name: "_value", Type: m.Elem()}
+		}
+	}
+	elem.Qualifier = param.Qualifier
+	elem.Scope = param.Scope
+	elem.Ctx = param.Ctx
+
+	return v.Inner.Bind(elem, decls)
+}
+
+func (v *ElemValidator) ExprString() string {
+	switch v.Name {
+	case "each":
+		if decl.IsArray(v.Param.Type) {
+			// v.Slice only accepts slices, so convert the array to a slice in advance.
+			elemType := v.typeString(decl.CoreType(v.Param.Type).(*types.Array).Elem())
+			return fmt.Sprintf("%s.Nested(func(_a %s) %s.Validator {\nreturn %s.Value(_a[:], %s)\n})",
+				v.Qualifier, v.Param.TypeString(), v.Qualifier, v.Qualifier, v.sliceExprString("[]"+elemType))
+		}
+		return v.sliceExprString(v.Param.TypeString())
+
+	case "keys", "values":
+		m := decl.CoreType(v.Param.Type).(*types.Map)
+		mapType := fmt.Sprintf("map[%s]%s", v.typeString(m.Key()), v.typeString(m.Elem()))
+		if types.Identical(v.Param.Type, m) {
+			return v.mapExprString(mapType)
+		}
+		// v.Map only accepts unnamed maps, so convert the named map in advance.
+		return fmt.Sprintf("%s.Nested(func(_m %s) %s.Validator {\nreturn %s.Value(%s(_m), %s)\n})",
+			v.Qualifier, v.Param.TypeString(), v.Qualifier, v.Qualifier, mapType, v.mapExprString(mapType))
+	}
+
+	return ""
+}
+
+func (v *ElemValidator) sliceExprString(sliceType string) string {
+	return fmt.Sprintf(`%s.Slice(func(_s %s) (_schemas []%s.Schema) {
+for _, _elem := range _s {
+_schemas = append(_schemas, %s.Value(_elem, %s))
+}
+return
+})`, v.Qualifier, sliceType, v.Qualifier, v.Qualifier, v.Inner.ExprString())
+}
+
+func (v *ElemValidator) mapExprString(mapType string) string {
+	keyType := v.typeString(decl.CoreType(v.Param.Type).(*types.Map).Key())
+
+	loop := "for _key := range _m {\n_schemas[_key] = %s.Value(_key, %s)\n}"
+	if v.Name == "values" {
+		loop = "for _key, _value := range _m {\n_schemas[_key] = %s.Value(_value, %s)\n}"
+	}
+	loop = fmt.Sprintf(loop, v.Qualifier, v.Inner.ExprString())
+
+	return fmt.Sprintf(`%s.Map(func(_m %s) map[%s]%s.Schema {
+_schemas := make(map[%s]%s.Schema)
+%s
+return _schemas
+})`, v.Qualifier, mapType, keyType, v.Qualifier, keyType, v.Qualifier, loop)
+}
+
+func (v *ElemValidator) typeString(typ types.Type) string {
+	return types.TypeString(typ, v.Param.Qualifier)
+}
+
+// verrInfo is the information about a leaf validator, which will be carried
+// by the structured errors (see package verr).
+type verrInfo struct {
+	Validator string
+	Args      []string
+	Code      string
+}
+
+// Info returns the information, in which the string literals are replaced
+// by their values.
+func (i verrInfo) Info() verr.Info {
+	info := verr.Info{Validator: i.Validator, Code: i.Code}
+	for _, arg := range i.Args {
+		// Use the values of string literals.
+		if s, err := strconv.Unquote(arg); err == nil {
+			arg = s
+		}
+		info.Args = append(info.Args, arg)
+	}
+	if code, err := strconv.Unquote(i.Code); err == nil {
+		info.Code = code
+	}
+	return info
+}
+
+// Wrap returns the expression string, which wraps the given leaf validator
+// expression by `verr.Leaf`.
+func (i verrInfo) Wrap(validator string) string {
+	fields := []string{"Validator: " + strconv.Quote(i.Validator)}
+	if len(i.Args) > 0 {
+		var args []string
+		for _, arg := range i.Info().Args {
+			args = append(args, strconv.Quote(arg))
+		}
+		fields = append(fields, "Args: []string{"+strings.Join(args, ", ")+"}")
+	}
+	if i.Code != "" {
+		fields = append(fields, "Code: "+i.Code)
+	}
+
+	return fmt.Sprintf("%s.Leaf(%s.Info{%s}, %s)", VerrQualifier, VerrQualifier, strings.Join(fields, ", "), validator)
+}
+
 func Parse(s string) (Validator, error) {
-	expr, err := parser.ParseExpr(s)
+	src, named := markNamedArgs(s)
+	expr, err := parser.ParseExpr(src)
 	if err != nil {
 		return nil, err
 	}
 	//ast.Print(token.NewFileSet(), expr)
 
-	v, err := Parser{S: s}.Parse(expr)
+	v, err := Parser{S: s, named: named}.parsePointer(expr)
 	if err != nil {
 		return nil, err
 	}
@@ -171,6 +667,9 @@ func Parse(s string) (Validator, error) {
 
 type Parser struct {
 	S string
+
+	// named holds the positions of the colons of the named arguments.
+	named map[token.Pos]bool
 }
 
 func (p Parser) Parse(e ast.Expr) (Validator, error) {
@@ -188,6 +687,7 @@ func (p Parser) Parse(e ast.Expr) (Validator, error) {
 				Left:      x,
 			}, nil
 		}
+		return nil, p.opError("!", expr.Op, expr.OpPos)
 
 	case *ast.BinaryExpr:
 		switch expr.Op {
@@ -227,10 +727,14 @@ func (p Parser) Parse(e ast.Expr) (Validator, error) {
 				Right:     y,
 			}, nil
 		}
+		return nil, p.opError("&& or ||", expr.Op, expr.OpPos)
 
 	case *ast.Ident:
 		// a
 		// _
+		if isPresence(expr.Name) {
+			return nil, p.presenceError(expr)
+		}
 		return &LeafValidator{
 			Name: expr.Name,
 		}, nil
@@ -238,64 +742,64 @@ func (p Parser) Parse(e ast.Expr) (Validator, error) {
 	case *ast.CallExpr:
 		switch fun := expr.Fun.(type) {
 		case *ast.Ident:
-			// a()
-			var args []string
-			for _, arg := range expr.Args {
-				argValue, _, err := p.parseCallArgExpr(arg)
+			switch fun.Name {
+			case "each", "keys", "values":
+				// each(a)
+				if len(expr.Args) != 1 {
+					return nil, p.error(fun.Name+"(...)", expr)
+				}
+				inner, err := p.parsePointer(expr.Args[0])
 				if err != nil {
 					return nil, err
 				}
-				args = append(args, argValue)
+				return &ElemValidator{
+					Qualifier: DefaultQualifier,
+					Name:      fun.Name,
+					Inner:     inner,
+				}, nil
 			}
-			return &LeafValidator{
-				Name: fun.Name,
-				Args: args,
-			}, nil
+
+			// a()
+			return p.parseLeafCall(fun.Name, expr.Args)
 
 		case *ast.SelectorExpr:
-			switch x := fun.X.(type) {
+			// a.msg("...")
+			// a().msg("...").code("...")
+			x, mods, err := p.parseModifiers(expr)
+			if err != nil {
+				return nil, err
+			}
+
+			var leaf *LeafValidator
+			switch x := x.(type) {
 			case *ast.Ident:
-				// a.b()
-				msg, err := p.parseMsgExpr(expr)
-				if err != nil {
-					return nil, err
+				if isPresence(x.Name) {
+					return nil, p.presenceError(x)
 				}
-				return &LeafValidator{
-					Name: x.Name,
-					Msg:  msg,
-				}, nil
+				leaf = &LeafValidator{Name: x.Name}
 
 			case *ast.CallExpr:
-				// a().b()
 				ident, ok := x.Fun.(*ast.Ident)
 				if !ok {
 					return nil, p.error("", x)
 				}
+				switch ident.Name {
+				case "each", "keys", "values":
+					return nil, p.error("a leaf validator", x)
+				}
 
-				msg, err := p.parseMsgExpr(expr)
+				leaf, err = p.parseLeafCall(ident.Name, x.Args)
 				if err != nil {
 					return nil, err
 				}
 
-				var args []string
-				for _, arg := range x.Args {
-					argValue, _, err := p.parseCallArgExpr(arg)
-					if err != nil {
-						return nil, err
-					}
-					args = append(args, argValue)
-				}
-
-				return &LeafValidator{
-					Name: ident.Name,
-					Args: args,
-					Msg:  msg,
-				}, nil
-
 			default:
 				return nil, p.error("", x)
 			}
 
+			leaf.Msg, leaf.Code = mods["msg"], mods["code"]
+			return leaf, nil
+
 		default:
 			return nil, p.error("", fun)
 		}
@@ -303,8 +807,90 @@ func (p Parser) Parse(e ast.Expr) (Validator, error) {
 	default:
 		return nil, p.error("", expr)
 	}
+}
+
+// parseLeafCall parses the arguments of the leaf validator name, which
+// may be positional (e.g. `len(1, 10)`) or named (e.g. `len(max: 10)`).
+func (p Parser) parseLeafCall(name string, callArgs []ast.Expr) (*LeafValidator, error) {
+	leaf := &LeafValidator{Name: name}
+	for _, arg := range callArgs {
+		var argName string
+		if b, ok := arg.(*ast.BinaryExpr); ok && p.named[b.OpPos] {
+			// max: 10
+			// ^^^
+			ident, ok := b.X.(*ast.Ident)
+			if !ok {
+				return nil, p.error("", b.X)
+			}
+			argName, arg = ident.Name, b.Y
+		} else if len(leaf.ArgNames) > 0 && leaf.ArgNames[len(leaf.ArgNames)-1] != "" {
+			return nil, p.error("a named argument", arg)
+		}
+
+		argValue, _, err := p.parseCallArgExpr(arg)
+		if err != nil {
+			return nil, err
+		}
+		leaf.Args = append(leaf.Args, argValue)
+		leaf.ArgNames = append(leaf.ArgNames, argName)
+		leaf.ArgPos = append(leaf.ArgPos, arg.Pos())
+	}
+	if !hasNamed(leaf.ArgNames) {
+		leaf.ArgNames = nil
+	}
+	return leaf, nil
+}
+
+func hasNamed(names []string) bool {
+	for _, name := range names {
+		if name != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// markNamedArgs replaces the colon of each named argument (e.g. `len(max: 10)`)
+// in s with `<`, which makes s a valid Go expression while keeping all the
+// positions, and returns the result along with the positions of the colons.
+func markNamedArgs(s string) (string, map[token.Pos]bool) {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(s))
+
+	var sc scanner.Scanner
+	sc.Init(file, []byte(s), nil, 0)
+
+	src := []byte(s)
+	named := make(map[token.Pos]bool)
+
+	var brackets []token.Token
+	prev := [2]token.Token{token.ILLEGAL, token.ILLEGAL}
+	for {
+		pos, tok, _ := sc.Scan()
+		if tok == token.EOF {
+			break
+		}
+
+		switch tok {
+		case token.LPAREN, token.LBRACK, token.LBRACE:
+			brackets = append(brackets, tok)
+		case token.RPAREN, token.RBRACK, token.RBRACE:
+			if len(brackets) > 0 {
+				brackets = brackets[:len(brackets)-1]
+			}
+		case token.COLON:
+			// A colon directly in parentheses, which follows an identifier at
+			// the beginning of an argument.
+			inParens := len(brackets) > 0 && brackets[len(brackets)-1] == token.LPAREN
+			if inParens && prev[1] == token.IDENT && (prev[0] == token.LPAREN || prev[0] == token.COMMA) {
+				src[file.Offset(pos)] = '<'
+				named[pos] = true
+			}
+		}
+		prev[0], prev[1] = prev[1], tok
+	}
 
-	return nil, nil
+	return string(src), named
 }
 
 func (p Parser) parseCallArgExpr(e ast.Expr) (string, token.Token, error) {
@@ -317,32 +903,101 @@ func (p Parser) parseCallArgExpr(e ast.Expr) (string, token.Token, error) {
 		// gt(min)
 		//    ^^^
 		return arg.Name, -1, nil
+	case *ast.SelectorExpr:
+		// lt(limits.MaxAge)
+		//    ^^^^^^^^^^^^^
+		if _, ok := arg.X.(*ast.Ident); !ok {
+			return "", -1, p.error("", e)
+		}
+		return p.string(arg), -1, nil
+	case *ast.UnaryExpr:
+		// gt(-1)
+		//    ^^
+		switch arg.Op {
+		case token.ADD, token.SUB, token.XOR:
+			if _, kind, err := p.parseCallArgExpr(arg.X); err != nil {
+				return "", -1, err
+			} else if kind == token.STRING {
+				return "", -1, p.error("", e)
+			}
+			return p.string(arg), -1, nil
+		}
+		return "", -1, p.error("", e)
+	case *ast.BinaryExpr:
+		// lt(1 << 20)
+		//    ^^^^^^^
+		switch arg.Op {
+		case token.ADD, token.SUB, token.MUL, token.QUO, token.REM,
+			token.AND, token.OR, token.XOR, token.AND_NOT, token.SHL, token.SHR:
+			if _, _, err := p.parseCallArgExpr(arg.X); err != nil {
+				return "", -1, err
+			}
+			if _, _, err := p.parseCallArgExpr(arg.Y); err != nil {
+				return "", -1, err
+			}
+			return p.string(arg), -1, nil
+		}
+		return "", -1, p.error("", e)
+	case *ast.ParenExpr:
+		// lt((1 << 20) - 1)
+		//    ^^^^^^^^^
+		if _, _, err := p.parseCallArgExpr(arg.X); err != nil {
+			return "", -1, err
+		}
+		return p.string(arg), -1, nil
 	default:
 		return "", -1, p.error("", e)
 	}
 }
 
-// parseMsgExpr extracts the custom error message from `msg("...")`.
-func (p Parser) parseMsgExpr(e *ast.CallExpr) (string, error) {
-	sel := e.Fun.(*ast.SelectorExpr)
-	if sel.Sel.Name != "msg" {
-		return "", p.error(p.string(sel.X)+".msg", sel)
+// parseModifiers extracts the modifiers (i.e. the custom error message from
+// `msg("...")` and the custom error code from `code("...")`) from e, and
+// returns the expression being modified along with the modifiers.
+func (p Parser) parseModifiers(e *ast.CallExpr) (ast.Expr, map[string]string, error) {
+	mods := make(map[string]string)
+
+	var x ast.Expr = e
+	for {
+		call, ok := x.(*ast.CallExpr)
+		if !ok {
+			return x, mods, nil
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return x, mods, nil
+		}
+
+		if err := p.parseModifier(call, sel, mods); err != nil {
+			return nil, nil, err
+		}
+		x = sel.X
 	}
+}
 
-	if len(e.Args) != 1 {
-		return "", p.error(p.string(sel.X)+".msg(\"...\")", sel)
+// parseModifier parses the modifier call, whose function is sel (e.g.
+// `a.msg("...")`), into mods.
+func (p Parser) parseModifier(call *ast.CallExpr, sel *ast.SelectorExpr, mods map[string]string) error {
+	name := sel.Sel.Name
+	if name != "msg" && name != "code" {
+		return p.error(p.string(sel.X)+".msg or "+p.string(sel.X)+".code", sel)
+	}
+	if _, ok := mods[name]; ok {
+		return p.error("at most one ."+name+"(...)", sel)
 	}
 
-	msg, kind, err := p.parseCallArgExpr(e.Args[0])
+	if len(call.Args) != 1 {
+		return p.error(p.string(sel.X)+"."+name+"(\"...\")", sel)
+	}
+	value, kind, err := p.parseCallArgExpr(call.Args[0])
 	if err != nil {
-		return "", err
+		return err
 	}
-
 	if kind != token.STRING {
-		return "", p.error("a string", e.Args[0])
+		return p.error("a string", call.Args[0])
 	}
 
-	return msg, nil
+	mods[name] = value
+	return nil
 }
 
 func (p Parser) string(e ast.Expr) string {
@@ -356,3 +1011,8 @@ func (p Parser) error(expected string, e ast.Expr) error {
 	}
 	return fmt.Errorf("1:%d expected %s, found %s", e.Pos(), expected, p.string(e))
 }
+
+// opError returns the error for the unsupported operator op at pos.
+func (p Parser) opError(expected string, op token.Token, pos token.Pos) error {
+	return fmt.Errorf("1:%d expected %s, found %s", pos, expected, op)
+}
@@ -69,7 +69,474 @@ func TestParse(t *testing.T) {
 				Name: "x",
 				Type: types.NewSlice(types.Typ[types.String]),
 			},
-			wantExprString: "v.LenSlice[[]string](0, 20).Msg(\"bad length\")",
+			wantExprString: `verr.Leaf(verr.Info{Validator: "len", Args: []string{"0", "20"}}, v.LenSlice[[]string](0, 20).Msg("bad length"))`,
+		},
+		{
+			name:  "each slice",
+			inStr: "len(1, 10) && each(len(1, 5))",
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.NewSlice(types.Typ[types.String]),
+			},
+			wantExprString: `v.All(verr.Leaf(verr.Info{Validator: "len", Args: []string{"1", "10"}}, v.LenSlice[[]string](1, 10)), v.Slice(func(_s []string) (_schemas []v.Schema) {
+for _, _elem := range _s {
+_schemas = append(_schemas, v.Value(_elem, verr.Leaf(verr.Info{Validator: "len", Args: []string{"1", "5"}}, v.LenString(1, 5))))
+}
+return
+}))`,
+		},
+		{
+			name:  "each array",
+			inStr: "each(gt(0))",
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.NewArray(types.Typ[types.Int], 3),
+			},
+			wantExprString: `v.Nested(func(_a [3]int) v.Validator {
+return v.Value(_a[:], v.Slice(func(_s []int) (_schemas []v.Schema) {
+for _, _elem := range _s {
+_schemas = append(_schemas, v.Value(_elem, verr.Leaf(verr.Info{Validator: "gt", Args: []string{"0"}}, v.Gt[int](0))))
+}
+return
+}))
+})`,
+		},
+		{
+			name:  "keys and values",
+			inStr: "keys(len(1, 5)) && values(gte(0))",
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.NewMap(types.Typ[types.String], types.Typ[types.Int]),
+			},
+			wantExprString: `v.All(v.Map(func(_m map[string]int) map[string]v.Schema {
+_schemas := make(map[string]v.Schema)
+for _key := range _m {
+_schemas[_key] = v.Value(_key, verr.Leaf(verr.Info{Validator: "len", Args: []string{"1", "5"}}, v.LenString(1, 5)))
+}
+return _schemas
+}), v.Map(func(_m map[string]int) map[string]v.Schema {
+_schemas := make(map[string]v.Schema)
+for _key, _value := range _m {
+_schemas[_key] = v.Value(_value, verr.Leaf(verr.Info{Validator: "gte", Args: []string{"0"}}, v.Gte[int](0)))
+}
+return _schemas
+}))`,
+		},
+		{
+			name:  "values named map",
+			inStr: "values(gte(0))",
+			inParam: expr.Param{
+				Name: "x",
+				Type: newNamed("Scores", types.NewMap(types.Typ[types.String], types.Typ[types.Int])),
+				Qualifier: func(pkg *types.Package) string {
+					return pkg.Name()
+				},
+			},
+			wantExprString: `v.Nested(func(_m pkg.Scores) v.Validator {
+return v.Value(map[string]int(_m), v.Map(func(_m map[string]int) map[string]v.Schema {
+_schemas := make(map[string]v.Schema)
+for _key, _value := range _m {
+_schemas[_key] = v.Value(_value, verr.Leaf(verr.Info{Validator: "gte", Args: []string{"0"}}, v.Gte[int](0)))
+}
+return _schemas
+}))
+})`,
+		},
+		{
+			name:  "code",
+			inStr: `match("^[a-z]+$").code("USR_001").msg("bad name")`,
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.Typ[types.String],
+			},
+			wantExprString: `verr.Leaf(verr.Info{Validator: "match", Args: []string{"^[a-z]+$"}, Code: "USR_001"}, v.Match(regexp.MustCompile("^[a-z]+$")).Msg("bad name"))`,
+		},
+		{
+			name:  "duplicate code",
+			inStr: `nonzero.code("A").code("B")`,
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.Typ[types.String],
+			},
+			wantErrStr: "1:1 expected at most one .code(...), found nonzero.code",
+		},
+		{
+			name:  "code on each",
+			inStr: `each(nonzero).code("A")`,
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.NewSlice(types.Typ[types.String]),
+			},
+			wantErrStr: "1:1 expected a leaf validator, found each(nonzero)",
+		},
+		{
+			name:  "pointer",
+			inStr: "len(1, 10)",
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.NewPointer(types.Typ[types.String]),
+			},
+			wantExprString: `v.Nested(func(_ptr *string) v.Validator {
+if _ptr == nil {
+return v.All()
+}
+return v.Value(*_ptr, verr.Leaf(verr.Info{Validator: "len", Args: []string{"1", "10"}}, v.LenString(1, 10)))
+})`,
+		},
+		{
+			name:  "required pointer",
+			inStr: `required.code("REQ") && len(1, 10) && email`,
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.NewPointer(types.Typ[types.String]),
+			},
+			wantExprString: `v.Nested(func(_ptr *string) v.Validator {
+if _ptr == nil {
+return verr.Leaf(verr.Info{Validator: "required", Code: "REQ"}, v.Nonzero[*string]().Msg("is required"))
+}
+return v.Value(*_ptr, v.All(verr.Leaf(verr.Info{Validator: "len", Args: []string{"1", "10"}}, v.LenString(1, 10)), verr.Leaf(verr.Info{Validator: "email"}, vext.Email())))
+})`,
+		},
+		{
+			name:  "required only",
+			inStr: "required",
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.NewPointer(types.Typ[types.Int]),
+			},
+			wantExprString: `verr.Leaf(verr.Info{Validator: "required"}, v.Nonzero[*int]().Msg("is required"))`,
+		},
+		{
+			name:  "optional elem",
+			inStr: "each(optional && gt(0))",
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.NewSlice(types.NewPointer(types.Typ[types.Int])),
+			},
+			wantExprString: `v.Slice(func(_s []*int) (_schemas []v.Schema) {
+for _, _elem := range _s {
+_schemas = append(_schemas, v.Value(_elem, v.Nested(func(_ptr *int) v.Validator {
+if _ptr == nil {
+return v.All()
+}
+return v.Value(*_ptr, verr.Leaf(verr.Info{Validator: "gt", Args: []string{"0"}}, v.Gt[int](0)))
+})))
+}
+return
+})`,
+		},
+		{
+			name:  "required non-pointer",
+			inStr: "required && len(1, 10)",
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.Typ[types.String],
+			},
+			wantErrStr: "cannot use `required` on non-pointer type string",
+		},
+		{
+			name:  "misplaced required",
+			inStr: "len(1, 10) && required",
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.NewPointer(types.Typ[types.String]),
+			},
+			wantErrStr: "1:15 unexpected required, which must be the leftmost operand of &&",
+		},
+		{
+			name:  "unsupported binary operator",
+			inStr: "len(1, 10) == 3",
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.Typ[types.String],
+			},
+			wantErrStr: "1:12 expected && or ||, found ==",
+		},
+		{
+			name:  "unsupported unary operator",
+			inStr: "-len",
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.Typ[types.String],
+			},
+			wantErrStr: "1:1 expected !, found -",
+		},
+		{
+			name:  "each string",
+			inStr: "each(len(1, 5))",
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.Typ[types.String],
+			},
+			wantErrStr: "cannot use validator `each` on type *types.Basic",
+		},
+		{
+			name:  "keys slice",
+			inStr: "keys(len(1, 5))",
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.NewSlice(types.Typ[types.String]),
+			},
+			wantErrStr: "cannot use validator `keys` on type *types.Slice",
+		},
+		{
+			name:  "negative arg",
+			inStr: "gt(-1)",
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.Typ[types.Int],
+			},
+			wantExprString: `verr.Leaf(verr.Info{Validator: "gt", Args: []string{"-1"}}, v.Gt[int](-1))`,
+		},
+		{
+			name:  "constant expression arg",
+			inStr: "lt((1 << 20) - 1)",
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.Typ[types.Int],
+			},
+			wantExprString: `verr.Leaf(verr.Info{Validator: "lt", Args: []string{"(1 << 20) - 1"}}, v.Lt[int]((1 << 20) - 1))`,
+		},
+		{
+			name:  "qualified constant arg",
+			inStr: "xrange(0, limits.MaxAge) && in(status.Active, status.Pending)",
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.Typ[types.Int],
+			},
+			wantExprString: `v.All(verr.Leaf(verr.Info{Validator: "xrange", Args: []string{"0", "limits.MaxAge"}}, v.Range[int](0, limits.MaxAge)), verr.Leaf(verr.Info{Validator: "in", Args: []string{"status.Active", "status.Pending"}}, v.In[int](status.Active, status.Pending)))`,
+		},
+		{
+			name:  "negative string arg",
+			inStr: `eq(-"a")`,
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.Typ[types.String],
+			},
+			wantErrStr: `1:4 unexpected -"a"`,
+		},
+		{
+			name:  "non-constant arg",
+			inStr: "gt(x.y.z)",
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.Typ[types.Int],
+			},
+			wantErrStr: "1:4 unexpected x.y.z",
+		},
+		{
+			name:  "named arg",
+			inStr: `len(max: 10)`,
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.Typ[types.String],
+			},
+			wantExprString: `verr.Leaf(verr.Info{Validator: "len", Args: []string{"0", "10"}}, v.LenString(0, 10))`,
+		},
+		{
+			name:  "default arg",
+			inStr: `len(1).msg("too short")`,
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.Typ[types.String],
+			},
+			wantExprString: `verr.Leaf(verr.Info{Validator: "len", Args: []string{"1", "math.MaxInt"}}, v.LenString(1, math.MaxInt).Msg("too short"))`,
+		},
+		{
+			name:  "positional and named args",
+			inStr: `xrange(1, max: 10)`,
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.Typ[types.Int],
+			},
+			wantExprString: `verr.Leaf(verr.Info{Validator: "xrange", Args: []string{"1", "10"}}, v.Range[int](1, 10))`,
+		},
+		{
+			name:  "unknown named arg",
+			inStr: `len(maxi: 10)`,
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.Typ[types.String],
+			},
+			wantErrStr: "1:11 unknown argument \"maxi\" for validator `len`",
+		},
+		{
+			name:  "positional after named arg",
+			inStr: `len(max: 10, 1)`,
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.Typ[types.String],
+			},
+			wantErrStr: "1:14 expected a named argument, found 1",
+		},
+		{
+			name:  "named arg given twice",
+			inStr: `len(1, min: 2)`,
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.Typ[types.String],
+			},
+			wantErrStr: "1:13 argument \"min\" of validator `len` is given twice",
+		},
+		{
+			name:  "missing named arg",
+			inStr: `xrange(max: 10)`,
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.Typ[types.Int],
+			},
+			wantErrStr: "missing argument \"min\" for validator `xrange`",
+		},
+		{
+			name:  "named arg type mismatch",
+			inStr: `len(max: "10")`,
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.Typ[types.String],
+			},
+			wantErrStr: "1:10 cannot use \"10\" (type untyped string) as int in argument to validator `len`",
+		},
+		{
+			name:  "named arg not accepted",
+			inStr: `email(x: 1)`,
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.Typ[types.String],
+			},
+			wantErrStr: "1:10 validator `email` does not accept named arguments",
+		},
+		{
+			name:  "ref",
+			inStr: "gt(start)",
+			inParam: expr.Param{
+				Name: "end",
+				Type: types.Typ[types.Int],
+				Scope: map[string]types.Type{
+					"start": types.Typ[types.Int],
+				},
+			},
+			wantExprString: `verr.Leaf(verr.Info{Validator: "gt", Args: []string{"start"}}, v.Gt[int](start).Msg("must be greater than start"))`,
+		},
+		{
+			name:  "ref lte",
+			inStr: "lte(end)",
+			inParam: expr.Param{
+				Name: "start",
+				Type: types.Typ[types.Int],
+				Scope: map[string]types.Type{
+					"end": types.Typ[types.Int],
+				},
+			},
+			wantExprString: `verr.Leaf(verr.Info{Validator: "lte", Args: []string{"end"}}, v.Lte[int](end).Msg("must be less than or equal to end"))`,
+		},
+		{
+			name:  "ref with msg",
+			inStr: `xrange(min, max).msg("out of range")`,
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.Typ[types.Int],
+				Scope: map[string]types.Type{
+					"min": types.Typ[types.Int],
+					"max": types.Typ[types.Int],
+				},
+			},
+			wantExprString: `verr.Leaf(verr.Info{Validator: "xrange", Args: []string{"min", "max"}}, v.Range[int](min, max).Msg("out of range"))`,
+		},
+		{
+			name:  "ref named like the elements",
+			inStr: "values(gt(value))",
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.NewMap(types.Typ[types.String], types.Typ[types.Int]),
+				Scope: map[string]types.Type{
+					"value": types.Typ[types.Int],
+				},
+			},
+			wantExprString: `v.Map(func(_m map[string]int) map[string]v.Schema {
+_schemas := make(map[string]v.Schema)
+for _key, _value := range _m {
+_schemas[_key] = v.Value(_value, verr.Leaf(verr.Info{Validator: "gt", Args: []string{"value"}}, v.Gt[int](value).Msg("must be greater than value")))
+}
+return _schemas
+})`,
+		},
+		{
+			name:  "ref type mismatch",
+			inStr: "gt(start)",
+			inParam: expr.Param{
+				Name: "end",
+				Type: types.Typ[types.Int],
+				Scope: map[string]types.Type{
+					"start": types.Typ[types.String],
+				},
+			},
+			wantErrStr: "1:4 cannot use start (type string) as int in argument to validator `gt`",
+		},
+		{
+			name:  "literal type mismatch",
+			inStr: `gt("10")`,
+			inParam: expr.Param{
+				Name: "age",
+				Type: types.Typ[types.Int],
+			},
+			wantErrStr: "1:4 cannot use \"10\" (type untyped string) as int in argument to validator `gt`",
+		},
+		{
+			name:  "len arg type mismatch",
+			inStr: `len(0, 10) && len("a", 3)`,
+			inParam: expr.Param{
+				Name: "name",
+				Type: types.Typ[types.String],
+			},
+			wantErrStr: "1:19 cannot use \"a\" (type untyped string) as int in argument to validator `len`",
+		},
+		{
+			name:  "time layout type mismatch",
+			inStr: "time(2006)",
+			inParam: expr.Param{
+				Name: "birthday",
+				Type: types.Typ[types.String],
+			},
+			wantErrStr: "1:6 cannot use 2006 (type untyped int) as string in argument to validator `time`",
+		},
+		{
+			name:  "arg overflows",
+			inStr: "gt(-1)",
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.Typ[types.Uint8],
+			},
+			wantErrStr: "1:4 cannot use -1 (type untyped int) as uint8 in argument to validator `gt` (overflows)",
+		},
+		{
+			name:  "invalid arg expression",
+			inStr: `lt(max + "1")`,
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.Typ[types.Int],
+				Scope: map[string]types.Type{
+					"max": types.Typ[types.Int],
+				},
+			},
+			wantErrStr: "1:4 invalid operation: max + \"1\" (mismatched types int and untyped string)",
+		},
+		{
+			name:  "unresolved arg",
+			inStr: "lt(MaxAge) && lt(limits.MaxAge)",
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.Typ[types.Int],
+			},
+			wantExprString: `v.All(verr.Leaf(verr.Info{Validator: "lt", Args: []string{"MaxAge"}}, v.Lt[int](MaxAge)), verr.Leaf(verr.Info{Validator: "lt", Args: []string{"limits.MaxAge"}}, v.Lt[int](limits.MaxAge)))`,
+		},
+		{
+			name:  "each elem type mismatch",
+			inStr: "each(len(1, 5))",
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.NewSlice(types.Typ[types.Int]),
+			},
+			wantErrStr: "cannot use validator `len` on type *types.Basic",
 		}, /*
 			{
 				name:  "match slice",
@@ -186,3 +653,210 @@ func newStruct(fields []*structField) *types.Struct {
 	}
 	return types.NewStruct(fs, tags)
 }
+
+func newNamed(name string, underlying types.Type) *types.Named {
+	pkg := types.NewPackage("example.com/pkg", "pkg")
+	return types.NewNamed(types.NewTypeName(0, pkg, name, nil), underlying, nil)
+}
+
+func TestParse_Ctx(t *testing.T) {
+	decls := map[string][]*decl.Validator{
+		"tenant": {
+			{
+				Qualifier:    "custom",
+				Name:         "Tenant",
+				Alias:        "tenant",
+				AllowedTypes: []string{"string"},
+				ArgNum:       decl.Range{Min: 0, Max: 1},
+				Ctx:          true,
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		inStr          string
+		inParam        expr.Param
+		wantExprString string
+		wantErrStr     string
+	}{
+		{
+			name:  "ctx",
+			inStr: `tenant("acme")`,
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.Typ[types.String],
+				Ctx:  "ctx",
+			},
+			wantExprString: `verr.Leaf(verr.Info{Validator: "tenant", Args: []string{"acme"}}, custom.Tenant(ctx, "acme"))`,
+		},
+		{
+			name:  "ctx elem",
+			inStr: `each(tenant)`,
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.NewSlice(types.Typ[types.String]),
+				Ctx:  "c",
+			},
+			wantExprString: `v.Slice(func(_s []string) (_schemas []v.Schema) {
+for _, _elem := range _s {
+_schemas = append(_schemas, v.Value(_elem, verr.Leaf(verr.Info{Validator: "tenant"}, custom.Tenant(c))))
+}
+return
+})`,
+		},
+		{
+			name:  "no ctx",
+			inStr: `tenant`,
+			inParam: expr.Param{
+				Name: "x",
+				Type: types.Typ[types.String],
+			},
+			wantErrStr: "validator `tenant` requires a context.Context, which is not available here",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator, err1 := expr.Parse(tt.inStr)
+
+			var err2 error
+			if validator != nil {
+				err2 = validator.Bind(tt.inParam, decls)
+			}
+			cmpError(t, err1, err2, tt.wantErrStr)
+
+			var gotExprString string
+			if err1 == nil && err2 == nil {
+				gotExprString = validator.ExprString()
+			}
+
+			if !cmp.Equal(gotExprString, tt.wantExprString) {
+				diff := cmp.Diff(gotExprString, tt.wantExprString)
+				t.Errorf("Want - Got: %s", diff)
+			}
+		})
+	}
+}
+
+func TestParse_Time(t *testing.T) {
+	timePkg := types.NewPackage("time", "time")
+	timeType := types.NewNamed(types.NewTypeName(0, timePkg, "Time", nil), types.NewStruct(nil, nil), nil)
+	durationType := types.NewNamed(types.NewTypeName(0, timePkg, "Duration", nil), types.Typ[types.Int64], nil)
+	timePkg.Scope().Insert(timeType.Obj())
+	timePkg.Scope().Insert(durationType.Obj())
+
+	tests := []struct {
+		name           string
+		inStr          string
+		inParam        expr.Param
+		wantExprString string
+		wantErrStr     string
+	}{
+		{
+			name:  "after date",
+			inStr: `after("2024-01-01")`,
+			inParam: expr.Param{
+				Name: "x",
+				Type: timeType,
+			},
+			wantExprString: `verr.Leaf(verr.Info{Validator: "after", Args: []string{"2024-01-01"}}, vtime.After(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))`,
+		},
+		{
+			name:  "before time with zone",
+			inStr: `before("2030-01-01T08:00:00+08:00")`,
+			inParam: expr.Param{
+				Name: "x",
+				Type: timeType,
+			},
+			wantExprString: `verr.Leaf(verr.Info{Validator: "before", Args: []string{"2030-01-01T08:00:00+08:00"}}, vtime.Before(time.Date(2030, 1, 1, 8, 0, 0, 0, time.FixedZone("", 28800))))`,
+		},
+		{
+			name:  "within",
+			inStr: `within("1h30m")`,
+			inParam: expr.Param{
+				Name: "x",
+				Type: timeType,
+			},
+			wantExprString: `verr.Leaf(verr.Info{Validator: "within", Args: []string{"1h30m"}}, vtime.Within(90 * time.Minute))`,
+		},
+		{
+			name:  "future",
+			inStr: `future`,
+			inParam: expr.Param{
+				Name: "x",
+				Type: timeType,
+			},
+			wantExprString: `verr.Leaf(verr.Info{Validator: "future"}, vtime.Future())`,
+		},
+		{
+			name:  "range duration",
+			inStr: `xrange("1s", "24h")`,
+			inParam: expr.Param{
+				Name: "x",
+				Type: durationType,
+			},
+			wantExprString: `verr.Leaf(verr.Info{Validator: "xrange", Args: []string{"1s", "24h"}}, v.Range[time.Duration](time.Second, 24 * time.Hour))`,
+		},
+		{
+			name:  "bad duration",
+			inStr: `within("1d")`,
+			inParam: expr.Param{
+				Name: "x",
+				Type: timeType,
+			},
+			wantErrStr: `1:8 bad duration "1d" (e.g. "1h30m")`,
+		},
+		{
+			name:  "bad time",
+			inStr: `after("01/02/2024")`,
+			inParam: expr.Param{
+				Name: "x",
+				Type: timeType,
+			},
+			wantErrStr: `1:7 bad time "01/02/2024" (e.g. "2006-01-02T15:04:05Z07:00" or "2006-01-02")`,
+		},
+	}
+
+	builtin, err := decl.Parse(decl.BuiltinDecls)
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+	decls := make(map[string][]*decl.Validator)
+	for _, d := range builtin {
+		decls[d.Alias] = append(decls[d.Alias], d)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator, err1 := expr.Parse(tt.inStr)
+
+			var err2 error
+			if validator != nil {
+				err2 = validator.Bind(tt.inParam, decls)
+			}
+			cmpError(t, err1, err2, tt.wantErrStr)
+
+			var gotExprString string
+			if err1 == nil && err2 == nil {
+				gotExprString = validator.ExprString()
+			}
+
+			if !cmp.Equal(gotExprString, tt.wantExprString) {
+				diff := cmp.Diff(gotExprString, tt.wantExprString)
+				t.Errorf("Want - Got: %s", diff)
+			}
+		})
+	}
+}
+
+func TestPointerValidator_Bind(t *testing.T) {
+	// A validator without the inner validator, which is not built by Parse.
+	validator := &expr.PointerValidator{Qualifier: expr.DefaultQualifier}
+	err := validator.Bind(expr.Param{Name: "x", Type: types.Typ[types.String]}, nil)
+
+	wantErrStr := "missing validator for type string"
+	if err == nil || err.Error() != wantErrStr {
+		t.Errorf("Err: got (%#v), want (%#v)", err, wantErrStr)
+	}
+}
@@ -0,0 +1,179 @@
+package expr
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+
+	"github.com/protogodev/validate/decl"
+	"github.com/protogodev/validate/verr"
+)
+
+// PointerValidator is an expression that represents the root validator of a
+// param (or an element), which will be dereferenced automatically if it is
+// a pointer. The presence keyword (i.e. `required` or `optional`) determines
+// what to do if the pointer is nil.
+type PointerValidator struct {
+	Qualifier string
+	Presence  string    // `required`, `optional` or empty (i.e. the same as `optional`).
+	Msg       string    // The custom error message for `required`.
+	Code      string    // The custom error code for `required`.
+	Inner     Validator // May be nil if only the presence keyword is specified.
+
+	Param Param
+}
+
+func (v *PointerValidator) Bind(param Param, decls map[string][]*decl.Validator) error {
+	v.Param = param
+
+	ptr, ok := param.Type.Underlying().(*types.Pointer)
+	if !ok {
+		if v.Presence != "" {
+			return fmt.Errorf("cannot use `%s` on non-pointer type %s", v.Presence, param.TypeString())
+		}
+		if v.Inner == nil {
+			return fmt.Errorf("missing validator for type %s", param.TypeString())
+		}
+		return v.Inner.Bind(param, decls)
+	}
+
+	if v.Inner == nil {
+		return nil
+	}
+	return v.Inner.Bind(Param{
+		Name:      "_ptr",
+		Type:      ptr.Elem(),
+		Qualifier: param.Qualifier,
+		Scope:     param.Scope,
+		Ctx:       param.Ctx,
+	}, decls)
+}
+
+func (v *PointerValidator) ExprString() string {
+	if !v.isPointer() {
+		return v.Inner.ExprString()
+	}
+
+	var nilValidator string
+	switch v.Presence {
+	case "required":
+		msg := v.Msg
+		if msg == "" {
+			msg = strconv.Quote("is required")
+		}
+		info := verrInfo{Validator: v.Presence, Code: v.Code}
+		nilValidator = info.Wrap(fmt.Sprintf("%s.Nonzero[%s]().Msg(%s)", v.Qualifier, v.Param.TypeString(), msg))
+		if v.Inner == nil {
+			return nilValidator
+		}
+	default:
+		// Skip validation if the pointer is nil.
+		nilValidator = v.Qualifier + ".All()"
+		if v.Inner == nil {
+			return nilValidator
+		}
+	}
+
+	return fmt.Sprintf(`%s.Nested(func(_ptr %s) %s.Validator {
+if _ptr == nil {
+return %s
+}
+return %s.Value(*_ptr, %s)
+})`, v.Qualifier, v.Param.TypeString(), v.Qualifier, nilValidator, v.Qualifier, v.Inner.ExprString())
+}
+
+// Info returns the information about the presence keyword `required`, which
+// will be carried by the structured errors.
+func (v *PointerValidator) Info() verr.Info {
+	return verrInfo{Validator: v.Presence, Code: v.Code}.Info()
+}
+
+func (v *PointerValidator) isPointer() bool {
+	_, ok := v.Param.Type.Underlying().(*types.Pointer)
+	return ok
+}
+
+// isPresence reports whether name is a presence keyword.
+func isPresence(name string) bool {
+	return name == "required" || name == "optional"
+}
+
+// parsePointer parses e into a PointerValidator. The leftmost operand of
+// `&&` in e may be a presence keyword, e.g. `required && len(1, 10)`.
+func (p Parser) parsePointer(e ast.Expr) (Validator, error) {
+	v := &PointerValidator{Qualifier: DefaultQualifier}
+
+	rest, err := p.splitPresence(e, v)
+	if err != nil {
+		return nil, err
+	}
+
+	if rest != nil {
+		v.Inner, err = p.Parse(rest)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return v, nil
+}
+
+// splitPresence extracts the presence keyword, if any, from e into v, and
+// returns the rest of e. The returned expression is nil if e consists of
+// the presence keyword only.
+func (p Parser) splitPresence(e ast.Expr, v *PointerValidator) (ast.Expr, error) {
+	switch expr := e.(type) {
+	case *ast.Ident:
+		// required
+		if isPresence(expr.Name) {
+			v.Presence = expr.Name
+			return nil, nil
+		}
+
+	case *ast.CallExpr:
+		// required.msg("...").code("...")
+		if _, ok := expr.Fun.(*ast.SelectorExpr); !ok {
+			break
+		}
+		x, mods, err := p.parseModifiers(expr)
+		if err != nil {
+			return nil, err
+		}
+		if ident, ok := x.(*ast.Ident); ok && isPresence(ident.Name) {
+			if ident.Name == "optional" {
+				return nil, p.error("no modifiers", expr)
+			}
+			v.Presence = ident.Name
+			v.Msg, v.Code = mods["msg"], mods["code"]
+			return nil, nil
+		}
+
+	case *ast.BinaryExpr:
+		// required && a
+		// required && a && b
+		if expr.Op != token.LAND {
+			break
+		}
+		x, err := p.splitPresence(expr.X, v)
+		if err != nil {
+			return nil, err
+		}
+		if v.Presence == "" {
+			break
+		}
+		if x == nil {
+			return expr.Y, nil
+		}
+		return &ast.BinaryExpr{X: x, OpPos: expr.OpPos, Op: expr.Op, Y: expr.Y}, nil
+	}
+
+	return e, nil
+}
+
+// presenceError returns the error for a presence keyword, which is not the
+// leftmost operand of `&&`.
+func (p Parser) presenceError(e *ast.Ident) error {
+	return fmt.Errorf("1:%d unexpected %s, which must be the leftmost operand of &&", e.Pos(), e.Name)
+}
@@ -0,0 +1,180 @@
+package expr
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/protogodev/validate/decl"
+)
+
+// timeLayouts are the accepted layouts of the time literals, which are tried
+// in order. The time literals without time zones are in UTC.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// durationUnits are the units used to represent the durations, which are
+// tried in order.
+var durationUnits = []struct {
+	name string
+	unit time.Duration
+}{
+	{"Hour", time.Hour},
+	{"Minute", time.Minute},
+	{"Second", time.Second},
+	{"Millisecond", time.Millisecond},
+	{"Microsecond", time.Microsecond},
+	{"Nanosecond", time.Nanosecond},
+}
+
+// convertTimeArgs converts the string literals (e.g. `"24h"`), which are
+// passed as the arguments of type time.Duration or time.Time, into the Go
+// expressions (e.g. `24 * time.Hour`). The literals are parsed here (i.e. at
+// generation time), thus the bad ones will be reported as errors.
+func (v *LeafValidator) convertTimeArgs(d *decl.Validator) error {
+	for i, arg := range v.Args {
+		want := v.argType(d, i)
+		if want == nil || !strings.HasPrefix(arg, `"`) && !strings.HasPrefix(arg, "`") {
+			continue
+		}
+		s, err := strconv.Unquote(arg)
+		if err != nil {
+			continue
+		}
+
+		var converted string
+		switch {
+		case decl.IsDuration(want):
+			dur, err := time.ParseDuration(s)
+			if err != nil {
+				return v.argError(i, "bad duration %s (e.g. \"1h30m\")", arg)
+			}
+			converted = durationExpr(dur)
+		case decl.IsTime(want):
+			t, err := parseTime(s)
+			if err != nil {
+				return v.argError(i, "bad time %s (e.g. \"2006-01-02T15:04:05Z07:00\" or \"2006-01-02\")", arg)
+			}
+			converted = timeExpr(t)
+		default:
+			continue
+		}
+
+		if v.literalArgs == nil {
+			v.literalArgs = append([]string(nil), v.Args...)
+		}
+		v.Args[i] = converted
+	}
+	return nil
+}
+
+// parseTime parses s per timeLayouts.
+func parseTime(s string) (t time.Time, err error) {
+	for _, layout := range timeLayouts {
+		if t, err = time.ParseInLocation(layout, s, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+	return t, err
+}
+
+// durationExpr returns the Go expression of d, e.g. `90 * time.Minute`.
+func durationExpr(d time.Duration) string {
+	if d == 0 {
+		return "time.Duration(0)"
+	}
+	for _, u := range durationUnits {
+		if d%u.unit != 0 {
+			continue
+		}
+		if n := d / u.unit; n != 1 {
+			return fmt.Sprintf("%d * time.%s", n, u.name)
+		}
+		return "time." + u.name
+	}
+	panic("unreachable")
+}
+
+// timeExpr returns the Go expression of t, e.g.
+// `time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)`.
+func timeExpr(t time.Time) string {
+	loc := "time.UTC"
+	if _, offset := t.Zone(); offset != 0 {
+		loc = fmt.Sprintf("time.FixedZone(%q, %d)", "", offset)
+	}
+	return fmt.Sprintf("time.Date(%d, %d, %d, %d, %d, %d, %d, %s)",
+		t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+}
+
+// EvalTime evaluates the time expression s, which is in the form of
+// `time.Date(year, month, day, hour, min, sec, nsec, loc)` (see timeExpr),
+// where loc is either `time.UTC` or `time.FixedZone(name, offset)`. It
+// reports false if s is not such an expression.
+func EvalTime(s string) (time.Time, bool) {
+	e, err := parser.ParseExpr(s)
+	if err != nil {
+		return time.Time{}, false
+	}
+	call, ok := e.(*ast.CallExpr)
+	if !ok || types.ExprString(call.Fun) != "time.Date" || len(call.Args) != 8 {
+		return time.Time{}, false
+	}
+
+	var ints [7]int
+	for i := range ints {
+		lit, ok := call.Args[i].(*ast.BasicLit)
+		if !ok || lit.Kind != token.INT {
+			return time.Time{}, false
+		}
+		ints[i], _ = strconv.Atoi(lit.Value)
+	}
+
+	loc := time.UTC
+	if types.ExprString(call.Args[7]) != "time.UTC" {
+		zone, ok := call.Args[7].(*ast.CallExpr)
+		if !ok || types.ExprString(zone.Fun) != "time.FixedZone" || len(zone.Args) != 2 {
+			return time.Time{}, false
+		}
+		offset, ok := EvalConst(types.ExprString(zone.Args[1]))
+		if !ok {
+			return time.Time{}, false
+		}
+		n, ok := constant.Int64Val(offset)
+		if !ok {
+			return time.Time{}, false
+		}
+		loc = time.FixedZone("", int(n))
+	}
+
+	return time.Date(ints[0], time.Month(ints[1]), ints[2], ints[3], ints[4], ints[5], ints[6], loc), true
+}
+
+// timeType returns the type name (e.g. `Duration`) declared in the package
+// time, which is looked up from the types of the param and the variables in
+// scope, or nil if the package is not found.
+func (p Param) timeType(name string) types.Type {
+	candidates := []types.Type{p.Type}
+	for _, typ := range p.Scope {
+		candidates = append(candidates, typ)
+	}
+
+	for _, typ := range candidates {
+		named, ok := typ.(*types.Named)
+		if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != "time" {
+			continue
+		}
+		if obj := named.Obj().Pkg().Scope().Lookup(name); obj != nil {
+			return obj.Type()
+		}
+	}
+	return nil
+}
@@ -2,7 +2,11 @@ package validate
 
 import (
 	_ "embed"
+	"errors"
 	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
 	"go/types"
 	"os"
 	"path/filepath"
@@ -12,7 +16,6 @@ import (
 	"github.com/protogodev/protogo/generator"
 	"github.com/protogodev/protogo/parser"
 	"github.com/protogodev/protogo/parser/ifacetool"
-	"github.com/protogodev/validate/decl"
 	"github.com/protogodev/validate/expr"
 )
 
@@ -22,27 +25,51 @@ var template string
 func init() {
 	protogocmd.MustRegister(&protogocmd.Plugin{
 		Name: "validate",
-		Cmd:  protogocmd.NewGen(&Generator{}),
+		Help: "Generate the validation middleware",
+		Cmd:  &Command{},
 	})
 }
 
 type Generator struct {
-	OutDir    string `name:"out" default:"." help:"output directory"`
-	Formatted bool   `name:"fmt" default:"true" help:"whether to make the generated code formatted"`
-	Custom    string `name:"custom" help:"the declaration file of custom validators"`
+	OutDir    string   `name:"out" default:"." help:"output directory"`
+	Formatted bool     `name:"fmt" default:"true" help:"whether to make the generated code formatted"`
+	Custom    []string `name:"custom" help:"the declarations of custom validators, which can be files, globs, directories or Go import paths (repeatable)"`
+
+	WarnUnknown bool `name:"warn-unknown" help:"warn about (instead of failing on) the annotation keys matching no param"`
+	Tests       bool `name:"tests" help:"also generate the table-driven tests of the validation middleware (validate_gen_test.go)"`
+	Fuzz        bool `name:"fuzz" help:"also generate the fuzz targets of the validation middleware (validate_gen_test.go)"`
+	Examples    bool `name:"examples" help:"also generate the example values of the method params satisfying the annotations (validate_examples.json)"`
+
+	// SrcFilename is the source file declaring the interface, whose imports
+	// may be referenced by the validator arguments (e.g. `lt(limits.MaxAge)`).
+	// It is set by the `validate` command, and is optional for the other
+	// callers of Generate.
+	SrcFilename string `kong:"-"`
 }
 
+// Generator is still a protogo generator (see protogocmd.NewGen).
+var _ protogocmd.Generator = (*Generator)(nil)
+
 func (g *Generator) PkgName() string {
 	return parser.PkgNameFromDir(g.OutDir)
 }
 
+// Generate generates the validation middleware for the interface.
 func (g *Generator) Generate(data *ifacetool.Data) (*generator.File, error) {
-	customDecls, err := getCustomDecls(g.Custom)
+	imports, builder, warnings, err := g.prepare(data)
 	if err != nil {
 		return nil, err
 	}
+	printWarnings(warnings)
 
-	completeDecls, imports := buildCompleteDecls(customDecls)
+	schemas := make(map[string]*methodSchema)
+	for _, method := range data.Methods {
+		schema, err := buildMethodSchema(builder, method, ParseDoc(method.Doc))
+		if err := g.filterUnknown(err); err != nil {
+			return nil, err
+		}
+		schemas[method.Name] = schema
+	}
 
 	tmplData := struct {
 		Imports []ifacetool.Import
@@ -52,45 +79,24 @@ func (g *Generator) Generate(data *ifacetool.Data) (*generator.File, error) {
 		Data:    data,
 	}
 
-	schemas := make(map[string]map[string]string)
-	for _, method := range data.Methods {
-		m := make(map[string]string)
-		options := ParseDoc(method.Doc)["schema"]
-		for _, opt := range options {
-			m[opt.K] = opt.V
-		}
-		schemas[method.Name] = m
-	}
-
 	return generator.Generate(template, tmplData, generator.Options{
 		Funcs: map[string]interface{}{
-			"nonCtxParams": func(params []*ifacetool.Param) (out []*ifacetool.Param) {
-				for _, p := range params {
-					if p.TypeString != "context.Context" {
-						out = append(out, p)
-					}
-				}
-				return
-			},
-			"methodSchema": func(methodName string) map[string]string {
+			"methodSchema": func(methodName string) *methodSchema {
 				return schemas[methodName]
 			},
-			"exprString": func(schema, paramName string, paramType types.Type) string {
-				validator, err := expr.Parse(schema)
-				if err != nil {
-					panic(err)
-				}
-
-				param := expr.Param{
-					Name: paramName,
-					Type: paramType,
-				}
-				if err := validator.Bind(param, completeDecls); err != nil {
-					panic(err)
+			"lastReturn": func(returns []*ifacetool.Param) *ifacetool.Param {
+				return returns[len(returns)-1]
+			},
+			"schemaVar": func(name string, fields []*schemaField) interface{} {
+				return struct {
+					Var    string
+					Fields []*schemaField
+				}{
+					Var:    name,
+					Fields: fields,
 				}
-
-				return validator.ExprString()
 			},
+			"join": strings.Join,
 			"returnErr": func(params []*ifacetool.Param, errFormat string) string {
 				var returns []string
 				for i := 0; i < len(params)-1; i++ {
@@ -106,52 +112,145 @@ func (g *Generator) Generate(data *ifacetool.Data) (*generator.File, error) {
 	})
 }
 
-func getCustomDecls(filename string) (string, error) {
-	if filename == "" {
-		return "", nil
+// prepare returns the imports of the generated code, along with the schema
+// builder and the warnings of the declarations, for the interface declared
+// in g.SrcFilename (if any).
+func (g *Generator) prepare(data *ifacetool.Data) ([]ifacetool.Import, *schemaBuilder, []error, error) {
+	var imports []ifacetool.Import
+	var dir string
+	if g.SrcFilename == "" {
+		for _, i := range data.Imports {
+			imports = mergeImports(imports, []ifacetool.Import{*i})
+		}
+	} else {
+		// The validator arguments may reference the constants from the packages
+		// imported by the source file (e.g. `lt(limits.MaxAge)`).
+		file, err := goparser.ParseFile(token.NewFileSet(), g.SrcFilename, nil, goparser.ImportsOnly)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		imports = sourceImports(data, file)
+		dir = filepath.Dir(g.SrcFilename)
 	}
 
-	b, err := os.ReadFile(filename)
+	completeDecls, err := buildCompleteDecls(g.Custom, imports)
 	if err != nil {
-		return "", err
+		return nil, nil, nil, err
 	}
 
-	return string(b), nil
+	builder := (&schemaBuilder{
+		decls:     completeDecls.validators,
+		qualifier: typeQualifier(data),
+	}).WithImports(imports, dir)
+	return mergeImports(completeDecls.imports, imports), builder, shadowWarnings(completeDecls), nil
 }
 
-func buildCompleteDecls(customDecls string) (map[string][]*decl.Validator, []ifacetool.Import) {
-	builtin, err := decl.Parse(decl.BuiltinDecls)
-	if err != nil {
-		panic(err)
+// buildMethodSchema builds the schema of the params (per `@schema`) and
+// the schema of the results (per `@returns`) for the given method, whose
+// annotations are annos. All the errors will be collected, and the schema
+// is always returned (excluding the erroneous options).
+func buildMethodSchema(b *schemaBuilder, method *ifacetool.Method, annos map[string][]Option) (*methodSchema, error) {
+	var errs schemaErrors
+	collect := func(anno string, es schemaErrors) {
+		for _, e := range es {
+			e.Method, e.Anno = method.Name, anno
+			errs = append(errs, e)
+		}
 	}
 
-	custom, err := decl.Parse(customDecls)
-	if err != nil {
-		panic(err)
+	params, ctx := methodParams(method)
+	b = b.WithContext(ctx)
+
+	pb := b.WithScope(params)
+	paramFields, es := pb.build(params, annos["schema"], expr.Parse)
+	collect("schema", es)
+	assertFields, es := pb.build(params, annos["assert"], expr.ParseAssert)
+	collect("assert", es)
+	schema := &methodSchema{Params: append(paramFields, assertFields...)}
+
+	returns := annos["returns"]
+	if len(returns) > 0 {
+		n := len(method.Returns)
+		if n == 0 || method.Returns[n-1].TypeString != "error" {
+			collect("returns", schemaErrors{{
+				Opt: Option{Pos: returns[0].Pos},
+				Err: fmt.Errorf("the last result must be an error"),
+			}})
+		} else {
+			// Exclude the last result (i.e. the error).
+			results := method.Returns[:n-1]
+			// Both the params and the results can be referenced.
+			rb := b.WithScope(append(params[:len(params):len(params)], results...))
+			schema.Returns, es = rb.build(results, returns, expr.Parse)
+			collect("returns", es)
+		}
 	}
 
-	decls := make(map[string][]*decl.Validator)
-	imports := make(map[string]string)
+	if len(errs) > 0 {
+		return schema, errs
+	}
+	return schema, nil
+}
 
-	for _, d := range builtin {
-		decls[d.Alias] = append(decls[d.Alias], d)
-		imports[d.Qualifier] = d.Import
+// filterUnknown reports the errors of unknown keys in err as warnings if
+// WarnUnknown is set, and returns the other errors.
+func (g *Generator) filterUnknown(err error) error {
+	var errs schemaErrors
+	if !g.WarnUnknown || !errors.As(err, &errs) {
+		return err
 	}
-	for _, d := range custom {
-		decls[d.Alias] = append(decls[d.Alias], d)
-		imports[d.Qualifier] = d.Import
+
+	unknown, others := errs.split()
+	for _, e := range unknown {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", e)
 	}
+	if len(others) > 0 {
+		return others
+	}
+	return nil
+}
 
-	var importList []ifacetool.Import
-	for alias, path := range imports {
-		importList = append(importList, ifacetool.Import{
-			Alias: alias,
-			Path:  path,
-		})
+// methodParams returns the params of method excluding the context, along
+// with the name of the context param if any.
+func methodParams(method *ifacetool.Method) (params []*ifacetool.Param, ctx string) {
+	for _, p := range method.Params {
+		if p.TypeString == "context.Context" {
+			ctx = p.Name
+			continue
+		}
+		params = append(params, p)
+	}
+	return
+}
 
+// sourceImports returns the imports of the interface, along with the ones
+// of the source file.
+func sourceImports(data *ifacetool.Data, file *ast.File) (imports []ifacetool.Import) {
+	for _, i := range data.Imports {
+		imports = mergeImports(imports, []ifacetool.Import{*i})
 	}
+	return mergeImports(imports, fileImports(file))
+}
 
-	return decls, importList
+// typeQualifier returns a qualifier, which qualifies the package-level
+// objects in the same way as the generated code imports their packages.
+func typeQualifier(data *ifacetool.Data) types.Qualifier {
+	return func(pkg *types.Package) string {
+		for _, i := range data.Imports {
+			if i.Path == pkg.Path() {
+				if i.Alias != "" {
+					return i.Alias
+				}
+				return pkg.Name()
+			}
+		}
+
+		if data.SrcPkgQualifier == "" && pkg.Name() == data.SrcPkgName {
+			// The generated code belongs to the source package.
+			return ""
+		}
+		return pkg.Name()
+	}
 }
 
 func emptyValue(param *ifacetool.Param) string {
@@ -7,6 +7,7 @@ require (
 	github.com/RussellLuo/vext v0.0.0-20220322111844-1844d4b0fc0e
 	github.com/google/go-cmp v0.5.8
 	github.com/protogodev/protogo v0.0.0-20230311092012-d4426dec5f4f
+	golang.org/x/tools v0.2.0
 )
 
 require (
@@ -16,5 +17,4 @@ require (
 	golang.org/x/exp v0.0.0-20220314205449-43aec2f8a4e7 // indirect
 	golang.org/x/mod v0.6.0 // indirect
 	golang.org/x/sys v0.1.0 // indirect
-	golang.org/x/tools v0.2.0 // indirect
 )
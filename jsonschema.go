@@ -0,0 +1,536 @@
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/constant"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	protogocmd "github.com/protogodev/protogo/cmd"
+	"github.com/protogodev/protogo/generator"
+	"github.com/protogodev/protogo/parser"
+	"github.com/protogodev/protogo/parser/ifacetool"
+	"github.com/protogodev/validate/decl"
+	"github.com/protogodev/validate/expr"
+)
+
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+func init() {
+	protogocmd.MustRegister(&protogocmd.Plugin{
+		Name: "validate-jsonschema",
+		Help: "Generate JSON Schema documents from @schema annotations",
+		Cmd:  protogocmd.NewGen(&JSONSchemaGenerator{}),
+	})
+}
+
+// JSONSchemaGenerator generates a JSON Schema document, which holds one
+// schema per method request (i.e. the params of the method), per the
+// `@schema` and `@assert` annotations.
+type JSONSchemaGenerator struct {
+	OutDir string   `name:"out" default:"." help:"output directory"`
+	Custom []string `name:"custom" help:"the declarations of custom validators, which can be files, globs, directories or Go import paths (repeatable)"`
+}
+
+func (g *JSONSchemaGenerator) PkgName() string {
+	return parser.PkgNameFromDir(g.OutDir)
+}
+
+func (g *JSONSchemaGenerator) Generate(data *ifacetool.Data) (*generator.File, error) {
+	completeDecls, err := buildCompleteDecls(g.Custom, nil)
+	if err != nil {
+		return nil, err
+	}
+	printWarnings(shadowWarnings(completeDecls))
+
+	builder := &schemaBuilder{
+		decls:     completeDecls.validators,
+		qualifier: typeQualifier(data),
+	}
+	defs := make(map[string]jsonSchema)
+	for _, method := range data.Methods {
+		schema, err := buildRequestJSONSchema(builder, method)
+		if err != nil {
+			return nil, err
+		}
+		defs[method.Name+"Request"] = schema
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(jsonSchema{
+		"$schema": jsonSchemaDraft,
+		"$defs":   defs,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &generator.File{
+		Name:    filepath.Join(g.OutDir, "validate_schema.json"),
+		Content: buf.Bytes(),
+	}, nil
+}
+
+// jsonSchema is a JSON Schema object, whose keys are the keywords.
+type jsonSchema map[string]interface{}
+
+// merge merges the keywords of other into s. The conflicting keywords will
+// be combined by `allOf`.
+func (s jsonSchema) merge(other jsonSchema) {
+	var conflicts jsonSchema
+	for k, v := range other {
+		if _, ok := s[k]; !ok {
+			s[k] = v
+			continue
+		}
+		if merged := mergeSubschemas(s[k], v); merged {
+			continue
+		}
+		if conflicts == nil {
+			conflicts = make(jsonSchema)
+		}
+		conflicts[k] = v
+	}
+	if conflicts != nil {
+		allOf, _ := s["allOf"].([]jsonSchema)
+		s["allOf"] = append(allOf, conflicts)
+	}
+}
+
+// mergeSubschemas merges b into a if both of them are subschemas (e.g. the
+// value of `items`) or maps of subschemas (e.g. the value of `properties`).
+func mergeSubschemas(a, b interface{}) bool {
+	switch a := a.(type) {
+	case jsonSchema:
+		if b, ok := b.(jsonSchema); ok {
+			a.merge(b)
+			return true
+		}
+	case map[string]jsonSchema:
+		if b, ok := b.(map[string]jsonSchema); ok {
+			for name, schema := range b {
+				if a[name] == nil {
+					a[name] = make(jsonSchema)
+				}
+				a[name].merge(schema)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// property returns the schema of the property name, which will be created
+// if not exists.
+func (s jsonSchema) property(name string) jsonSchema {
+	props, ok := s["properties"].(map[string]jsonSchema)
+	if !ok {
+		props = make(map[string]jsonSchema)
+		s["properties"] = props
+	}
+	if props[name] == nil {
+		props[name] = make(jsonSchema)
+	}
+	return props[name]
+}
+
+// set sets the keyword to value, unless value is nil (i.e. unset).
+func (s jsonSchema) set(keyword string, value interface{}) {
+	if value != nil {
+		s[keyword] = value
+	}
+}
+
+// require marks the property name as required.
+func (s jsonSchema) require(name string) {
+	required, _ := s["required"].([]string)
+	for _, r := range required {
+		if r == name {
+			return
+		}
+	}
+	s["required"] = append(required, name)
+}
+
+// buildRequestJSONSchema builds the JSON Schema of the params of method.
+func buildRequestJSONSchema(b *schemaBuilder, method *ifacetool.Method) (jsonSchema, error) {
+	annos := ParseDoc(method.Doc)
+
+	params, ctx := methodParams(method)
+	b = b.WithScope(params).WithContext(ctx)
+
+	schema := jsonSchema{"type": "object"}
+	for _, p := range params {
+		schema.property(p.Name).merge(typeJSONSchema(p.Type, nil))
+	}
+
+	for _, anno := range []struct {
+		name  string
+		parse parseFunc
+	}{
+		{name: "schema", parse: expr.Parse},
+		{name: "assert", parse: expr.ParseAssert},
+	} {
+		for _, opt := range annos[anno.name] {
+			if err := b.buildJSONSchemaField(schema, params, opt, anno.parse); err != nil {
+				return nil, fmt.Errorf("method %s: @%s: %s: %v", method.Name, anno.name, opt.K, err)
+			}
+		}
+	}
+
+	return schema, nil
+}
+
+func (b *schemaBuilder) buildJSONSchemaField(schema jsonSchema, vars []*ifacetool.Param, opt Option, parse parseFunc) error {
+	path := strings.Split(opt.K, ".")
+	var p *ifacetool.Param
+	for _, v := range vars {
+		if v.Name == path[0] {
+			p = v
+		}
+	}
+	if p == nil {
+		return b.unmatchedError(vars, opt).Err
+	}
+
+	parent, name, typ := schema, p.Name, p.Type
+	for _, fieldName := range path[1:] {
+		if ptr, ok := typ.Underlying().(*types.Pointer); ok {
+			typ = ptr.Elem()
+		}
+
+		field, err := b.lookupField(typ, fieldName)
+		if err != nil {
+			return err
+		}
+
+		parent, name, typ = parent.property(name), jsonFieldName(typ, field), field.Type()
+	}
+
+	validator, err := b.bind(opt.K, typ, opt.V, parse)
+	if err != nil {
+		return err
+	}
+
+	keywords, required := validatorJSONSchema(validator)
+	parent.property(name).merge(keywords)
+	if required {
+		parent.require(name)
+	}
+	return nil
+}
+
+// validatorJSONSchema converts the bound validator to JSON Schema keywords,
+// and reports whether the value is required.
+func validatorJSONSchema(validator expr.Validator) (schema jsonSchema, required bool) {
+	schema = make(jsonSchema)
+
+	switch v := validator.(type) {
+	case *expr.PointerValidator:
+		if v.Inner != nil {
+			schema, _ = validatorJSONSchema(v.Inner)
+		}
+		return schema, v.Presence == "required"
+
+	case *expr.LogicValidator:
+		left, _ := validatorJSONSchema(v.Left)
+		switch v.Name {
+		case "!":
+			schema["not"] = left
+		case "&&":
+			right, _ := validatorJSONSchema(v.Right)
+			schema.merge(left)
+			schema.merge(right)
+		case "||":
+			right, _ := validatorJSONSchema(v.Right)
+			schema["anyOf"] = []jsonSchema{left, right}
+		}
+
+	case *expr.ElemValidator:
+		inner, _ := validatorJSONSchema(v.Inner)
+		switch v.Name {
+		case "each":
+			schema["items"] = inner
+		case "keys":
+			schema["propertyNames"] = inner
+		case "values":
+			schema["additionalProperties"] = inner
+		}
+
+	case *expr.AssertValidator:
+		// Assertions cannot be expressed in JSON Schema.
+		schema["x-assert"] = []string{v.Expr}
+
+	case *expr.LeafValidator:
+		schema = leafJSONSchema(v)
+	}
+
+	return schema, false
+}
+
+// builtinImports are the import paths of the builtin validators. Note that
+// the time validators (i.e. vtime) have no equivalents in JSON Schema, thus
+// are represented by the extension keywords (e.g. `x-after`).
+var builtinImports = map[string]bool{
+	"github.com/RussellLuo/validating/v3": true,
+	"github.com/RussellLuo/vext":          true,
+}
+
+// timeFormats maps the time layouts to the formats in JSON Schema.
+var timeFormats = map[string]string{
+	"2006-01-02T15:04:05Z07:00": "date-time",
+	"2006-01-02":                "date",
+	"15:04:05":                  "time",
+}
+
+func leafJSONSchema(v *expr.LeafValidator) jsonSchema {
+	schema := make(jsonSchema)
+
+	d := v.MatchedDecl()
+	if d == nil {
+		// Validator `_` is represented by the type of the struct.
+		return schema
+	}
+
+	args := make([]interface{}, len(v.Args))
+	for i, arg := range v.Args {
+		value, ok := jsonLiteral(arg)
+		if !ok || strings.HasPrefix(arg, "math.") {
+			if v.Defaulted(i) {
+				// Leave the non-literal default value (e.g. `math.MaxInt`,
+				// which means no limit) unset, along with the keyword it
+				// belongs to.
+				continue
+			}
+			// References to other variables cannot be expressed in JSON Schema.
+			return schema
+		}
+		args[i] = value
+	}
+
+	if d.JSONSchema != "" || !builtinImports[d.Import] {
+		keyword := d.JSONSchema
+		if keyword == "" {
+			keyword = "x-" + v.Name
+		}
+		switch len(args) {
+		case 0:
+			schema[keyword] = true
+		case 1:
+			schema[keyword] = args[0]
+		default:
+			schema[keyword] = args
+		}
+		return schema
+	}
+
+	typ := v.Param.Type
+	switch v.Name {
+	case "nonzero":
+		if decl.IsString(typ) {
+			schema["minLength"] = 1
+		} else {
+			schema["not"] = jsonSchema{"const": zeroJSONValue(typ)}
+		}
+	case "zero":
+		schema["const"] = zeroJSONValue(typ)
+	case "len", "runecnt":
+		switch {
+		case decl.IsString(typ):
+			schema.set("minLength", args[0])
+			schema.set("maxLength", args[1])
+		case decl.IsBytes(typ):
+			// The length of the base64-encoded string is irrelevant.
+		default:
+			schema.set("minItems", args[0])
+			schema.set("maxItems", args[1])
+		}
+	case "eq":
+		schema["const"] = args[0]
+	case "ne":
+		schema["not"] = jsonSchema{"const": args[0]}
+	case "gt", "gte", "lt", "lte", "xrange":
+		if decl.IsString(typ) {
+			// Strings cannot be compared in JSON Schema.
+			break
+		}
+		switch v.Name {
+		case "gt":
+			schema["exclusiveMinimum"] = args[0]
+		case "gte":
+			schema["minimum"] = args[0]
+		case "lt":
+			schema["exclusiveMaximum"] = args[0]
+		case "lte":
+			schema["maximum"] = args[0]
+		case "xrange":
+			schema.set("minimum", args[0])
+			schema.set("maximum", args[1])
+		}
+	case "in":
+		schema["enum"] = args
+	case "nin":
+		schema["not"] = jsonSchema{"enum": args}
+	case "match":
+		schema["pattern"] = args[0]
+	case "email":
+		schema["format"] = "email"
+	case "ip":
+		schema["anyOf"] = []jsonSchema{{"format": "ipv4"}, {"format": "ipv6"}}
+	case "time":
+		layout, _ := args[0].(string)
+		if format, ok := timeFormats[layout]; ok {
+			schema["format"] = format
+		} else {
+			schema["x-time-layout"] = layout
+		}
+	}
+
+	return schema
+}
+
+// jsonLiteral converts the Go literal to the corresponding JSON value. It
+// returns false if arg is not a literal (e.g. a reference to a variable).
+func jsonLiteral(arg string) (interface{}, bool) {
+	if s, err := strconv.Unquote(arg); err == nil {
+		return s, true
+	}
+	if n, err := strconv.ParseInt(arg, 0, 64); err == nil {
+		return n, true
+	}
+	if f, err := strconv.ParseFloat(arg, 64); err == nil {
+		return f, true
+	}
+
+	if t, ok := expr.EvalTime(arg); ok {
+		return t.Format(time.RFC3339Nano), true
+	}
+
+	// Evaluate the constant expressions (e.g. `1 << 20` or `24 * time.Hour`).
+	value, ok := expr.EvalConst(arg)
+	if !ok {
+		return nil, false
+	}
+	switch value.Kind() {
+	case constant.String:
+		return constant.StringVal(value), true
+	case constant.Int:
+		if n, ok := constant.Int64Val(value); ok {
+			return n, true
+		}
+	case constant.Float:
+		f, _ := constant.Float64Val(value)
+		return f, true
+	}
+	return nil, false
+}
+
+func zeroJSONValue(typ types.Type) interface{} {
+	if b, ok := typ.Underlying().(*types.Basic); ok {
+		switch info := b.Info(); {
+		case info&types.IsString != 0:
+			return ""
+		case info&types.IsBoolean != 0:
+			return false
+		case info&types.IsNumeric != 0:
+			return 0
+		}
+	}
+	return nil
+}
+
+// typeJSONSchema returns the JSON Schema of the Go type typ.
+func typeJSONSchema(typ types.Type, visiting map[*types.Named]bool) jsonSchema {
+	if named, ok := typ.(*types.Named); ok {
+		obj := named.Obj()
+		if obj.Pkg() != nil && obj.Pkg().Path() == "time" && obj.Name() == "Time" {
+			return jsonSchema{"type": "string", "format": "date-time"}
+		}
+
+		if visiting[named] {
+			// Stop at the recursive type.
+			return jsonSchema{"type": "object"}
+		}
+		if visiting == nil {
+			visiting = make(map[*types.Named]bool)
+		}
+		visiting[named] = true
+		defer delete(visiting, named)
+	}
+
+	switch t := typ.Underlying().(type) {
+	case *types.Basic:
+		switch info := t.Info(); {
+		case info&types.IsBoolean != 0:
+			return jsonSchema{"type": "boolean"}
+		case info&types.IsInteger != 0:
+			return jsonSchema{"type": "integer"}
+		case info&types.IsFloat != 0:
+			return jsonSchema{"type": "number"}
+		case info&types.IsString != 0:
+			return jsonSchema{"type": "string"}
+		}
+	case *types.Pointer:
+		return typeJSONSchema(t.Elem(), visiting)
+	case *types.Slice:
+		if decl.IsBytes(t) {
+			return jsonSchema{"type": "string", "contentEncoding": "base64"}
+		}
+		return jsonSchema{"type": "array", "items": typeJSONSchema(t.Elem(), visiting)}
+	case *types.Array:
+		return jsonSchema{
+			"type":     "array",
+			"items":    typeJSONSchema(t.Elem(), visiting),
+			"minItems": t.Len(),
+			"maxItems": t.Len(),
+		}
+	case *types.Map:
+		return jsonSchema{"type": "object", "additionalProperties": typeJSONSchema(t.Elem(), visiting)}
+	case *types.Struct:
+		schema := jsonSchema{"type": "object"}
+		for i := 0; i < t.NumFields(); i++ {
+			field := t.Field(i)
+			if !field.Exported() {
+				continue
+			}
+			tag := reflect.StructTag(t.Tag(i))
+			if tag.Get("json") == "-" {
+				continue
+			}
+			fieldSchema := typeJSONSchema(field.Type(), visiting)
+			if field.Embedded() && tag.Get("json") == "" {
+				// The fields of an embedded struct are promoted.
+				if props, ok := fieldSchema["properties"].(map[string]jsonSchema); ok {
+					for name, prop := range props {
+						schema.property(name).merge(prop)
+					}
+					continue
+				}
+			}
+			schema.property(fieldName(field.Name(), tag)).merge(fieldSchema)
+		}
+		return schema
+	}
+
+	// Any value is allowed.
+	return jsonSchema{}
+}
+
+// jsonFieldName returns the JSON name of the given field of the struct typ.
+func jsonFieldName(typ types.Type, field *types.Var) string {
+	s := typ.Underlying().(*types.Struct)
+	for i := 0; i < s.NumFields(); i++ {
+		if s.Field(i) == field {
+			return fieldName(field.Name(), reflect.StructTag(s.Tag(i)))
+		}
+	}
+	return field.Name()
+}
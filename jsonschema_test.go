@@ -0,0 +1,133 @@
+package validate
+
+import (
+	"go/types"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/protogodev/protogo/parser/ifacetool"
+	"github.com/protogodev/validate/decl"
+)
+
+func TestBuildRequestJSONSchema(t *testing.T) {
+	pkg := types.NewPackage("example.com/user", "user")
+	address := types.NewNamed(types.NewTypeName(0, pkg, "Address", nil), types.NewStruct([]*types.Var{
+		types.NewField(0, pkg, "Zip", types.Typ[types.String], false),
+		types.NewField(0, pkg, "Note", types.Typ[types.String], false),
+	}, []string{`json:"zip"`, `json:"-"`}), nil)
+
+	ctxType := types.NewNamed(types.NewTypeName(0, types.NewPackage("context", "context"), "Context", nil), types.NewInterfaceType(nil, nil), nil)
+
+	custom := &decl.Validator{
+		Qualifier:    "custom",
+		Name:         "UUID",
+		Alias:        "uuid",
+		AllowedTypes: []string{"string"},
+		JSONSchema:   "x-uuid",
+	}
+	builtin, err := decl.Parse(decl.BuiltinDecls)
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+	decls := map[string][]*decl.Validator{"uuid": {custom}}
+	for _, d := range builtin {
+		decls[d.Alias] = append(decls[d.Alias], d)
+	}
+
+	b := &schemaBuilder{
+		decls: decls,
+		qualifier: func(p *types.Package) string {
+			return p.Name()
+		},
+	}
+
+	method := &ifacetool.Method{
+		Name: "Update",
+		Doc: []string{
+			"// @schema:",
+			"//   id: uuid",
+			"//   tags: len(1, 5) && each(in(\"a\", \"b\") && len(max: 1 << 4))",
+			"//   id: len(min: 36)",
+			"//   age: gte(0) && lt(max)",
+			"//   address: required",
+			"//   address.Zip: match(`^\\d{5}$`)",
+			"//   birthday: time(\"2006-01-02\")",
+			"//",
+			"// @assert:",
+			"//   max: max > 0",
+		},
+		Params: []*ifacetool.Param{
+			{Name: "ctx", TypeString: "context.Context", Type: ctxType},
+			{Name: "id", Type: types.Typ[types.String]},
+			{Name: "tags", Type: types.NewSlice(types.Typ[types.String])},
+			{Name: "age", Type: types.Typ[types.Int]},
+			{Name: "max", Type: types.Typ[types.Int]},
+			{Name: "address", Type: types.NewPointer(address)},
+			{Name: "birthday", Type: types.Typ[types.String]},
+		},
+	}
+
+	got, err := buildRequestJSONSchema(b, method)
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+
+	want := jsonSchema{
+		"type": "object",
+		"properties": map[string]jsonSchema{
+			"id": {"type": "string", "x-uuid": true, "minLength": int64(36)},
+			"tags": {
+				"type":     "array",
+				"items":    jsonSchema{"type": "string", "enum": []interface{}{"a", "b"}, "minLength": int64(0), "maxLength": int64(16)},
+				"minItems": int64(1),
+				"maxItems": int64(5),
+			},
+			// lt(max) references another param, which cannot be expressed.
+			"age": {"type": "integer", "minimum": int64(0)},
+			"max": {"type": "integer", "x-assert": []string{"max > 0"}},
+			"address": {
+				"type": "object",
+				"properties": map[string]jsonSchema{
+					"zip": {"type": "string", "pattern": `^\d{5}$`},
+				},
+			},
+			"birthday": {"type": "string", "format": "date"},
+		},
+		"required": []string{"address"},
+	}
+
+	if !cmp.Equal(got, want) {
+		diff := cmp.Diff(got, want)
+		t.Errorf("Want - Got: %s", diff)
+	}
+}
+
+func TestJSONLiteral(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   interface{}
+		wantOK bool
+	}{
+		{in: `1 << 4`, want: int64(16), wantOK: true},
+		{in: `"a"`, want: "a", wantOK: true},
+		{in: `90 * time.Minute`, want: int64(5400000000000), wantOK: true},
+		{in: `time.Duration(0)`, want: int64(0), wantOK: true},
+		{in: `time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)`, want: "2024-01-01T00:00:00Z", wantOK: true},
+		{in: `time.Date(2030, 1, 1, 8, 0, 0, 0, time.FixedZone("", 28800))`, want: "2030-01-01T08:00:00+08:00", wantOK: true},
+		{in: `time.Now()`, wantOK: false},
+		{in: `max`, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, ok := jsonLiteral(tt.in)
+			if ok != tt.wantOK {
+				t.Fatalf("OK: got (%v), want (%v)", ok, tt.wantOK)
+			}
+			if !cmp.Equal(got, tt.want) {
+				diff := cmp.Diff(got, tt.want)
+				t.Errorf("Want - Got: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package validate
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/protogodev/validate/decl"
+)
+
+// ListValidators writes the resolved table of the builtin validators and the
+// custom ones (if any) to w. The declarations of each alias are listed in the
+// order of precedence, i.e. the first one allowing the type of a value will
+// be used to validate the value.
+func (g *Generator) ListValidators(w io.Writer) error {
+	decls, err := buildCompleteDecls(g.Custom, nil)
+	if err != nil {
+		return err
+	}
+	return writeValidators(w, decls)
+}
+
+// writeValidators writes the validators of decls in a table, where each
+// custom declaration is marked with the file declaring it.
+func writeValidators(w io.Writer, decls *completeDecls) error {
+	sources := make(map[*decl.Validator]string)
+	for _, f := range decls.custom {
+		for _, d := range f.validators {
+			sources[d] = f.filename
+		}
+	}
+
+	aliases := make([]string, 0, len(decls.validators))
+	for alias := range decls.validators {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ALIAS\tTYPE\tARGS\tCONSTRUCTOR\tSOURCE")
+	for _, alias := range aliases {
+		for _, d := range decls.validators[alias] {
+			constructor := d.Entry()
+			if d.IsGeneric {
+				constructor += "[T]"
+			}
+
+			source, ok := sources[d]
+			if !ok {
+				source = "builtin"
+			}
+			if shadows := decls.shadows[d]; len(shadows) > 0 {
+				var entries []string
+				for _, s := range shadows {
+					entries = append(entries, s.Entry())
+				}
+				verb := "shadows"
+				if d.Override {
+					verb = "overrides"
+				}
+				source += fmt.Sprintf(" (%s %s)", verb, strings.Join(entries, ", "))
+			}
+
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", alias, strings.Join(d.AllowedTypes, "|"), formatArgs(d), constructor, source)
+		}
+	}
+	return tw.Flush()
+}
+
+// formatArgs formats the arguments of d in the same way as they are declared
+// (e.g. `min:int=0,max:int=math.MaxInt`), where a leading `ctx` indicates
+// a context-aware validator.
+func formatArgs(d *decl.Validator) string {
+	var args []string
+	if d.Ctx {
+		args = append(args, "ctx")
+	}
+
+	switch {
+	case len(d.Args) > 0:
+		for _, arg := range d.Args {
+			s := arg.Kind
+			if arg.Name != "" {
+				s = arg.Name + ":" + s
+			}
+			if arg.Variadic {
+				s += "..."
+			}
+			if arg.Default != "" {
+				s += "=" + arg.Default
+			}
+			args = append(args, s)
+		}
+	case d.ArgNum.Max == math.MaxInt:
+		args = append(args, fmt.Sprintf("%d+", d.ArgNum.Min))
+	case d.ArgNum.Min == d.ArgNum.Max:
+		if d.Ctx && d.ArgNum.Max == 0 {
+			break // Only `ctx`.
+		}
+		args = append(args, fmt.Sprint(d.ArgNum.Min))
+	default:
+		args = append(args, fmt.Sprintf("%d-%d", d.ArgNum.Min, d.ArgNum.Max))
+	}
+
+	return strings.Join(args, ",")
+}
@@ -0,0 +1,67 @@
+package validate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/protogodev/validate/decl"
+)
+
+func TestWriteValidators(t *testing.T) {
+	builtin, err := decl.Parse(`package builtin
+
+import "example.com/v"
+
+var _ = []any{
+	// name=len type=string args=min:int=0,max:int=math.MaxInt
+	v.LenString,
+
+	// type=comparable args=T,T...
+	v.In[T],
+}
+`)
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+	custom, err := decl.Parse(`package custom
+
+import "example.com/c"
+
+var _ = []any{
+	// name=len type=string override=true args=2
+	c.Len,
+
+	// type=string ctx=true args=0
+	c.Tenant,
+}
+`)
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+
+	validators, shadows, err := decl.Resolve(builtin, custom)
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+	decls := &completeDecls{
+		validators: validators,
+		shadows:    shadows,
+		custom:     []*declFile{{filename: "decl.go", validators: custom}},
+	}
+
+	var b strings.Builder
+	if err := writeValidators(&b, decls); err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+
+	want := `ALIAS   TYPE        ARGS                           CONSTRUCTOR  SOURCE
+in      comparable  T,T...                         v.In[T]      builtin
+len     string      2                              c.Len        decl.go (overrides v.LenString)
+len     string      min:int=0,max:int=math.MaxInt  v.LenString  builtin
+tenant  string      ctx                            c.Tenant     decl.go
+`
+	if got := b.String(); got != want {
+		t.Errorf("Want - Got: %s", cmp.Diff(got, want))
+	}
+}
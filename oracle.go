@@ -0,0 +1,280 @@
+package validate
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"github.com/protogodev/protogo/parser/ifacetool"
+	"github.com/protogodev/validate/decl"
+)
+
+// The import paths of the packages, which are referenced by the oracle.
+const (
+	validatingPath = "github.com/RussellLuo/validating/v3"
+	vextPath       = "github.com/RussellLuo/vext"
+)
+
+// oracleImports are the imports required by the oracle, which will be
+// removed from the generated code if unused.
+var oracleImports = []ifacetool.Import{
+	{Path: "net"},
+	{Path: "regexp"},
+	{Path: "time"},
+	{Path: "unicode/utf8"},
+	{Path: validatingPath},
+	{Path: vextPath},
+}
+
+// oracle translates the schema expressions into the Go boolean expressions,
+// which report whether the params satisfy them, e.g. `len(name) >= 1 &&
+// len(name) <= 10` for `name: len(1, 10)`.
+//
+// The fuzz targets check the middleware against the oracle, which must be
+// independent of the code generator. Therefore, the schema expressions are
+// interpreted per the semantics of the builtin validators on their own,
+// instead of by packages expr, decl or runtime. Only `email` is delegated to
+// its implementation, which has no simpler specification.
+type oracle struct {
+	// decls are the declarations of the validators, which tell whether a
+	// builtin validator has been overridden by a custom one (i.e. whether
+	// the first declaration matching the param type is from another package).
+	decls map[string][]*decl.Validator
+	// qualifiers maps the import paths to the qualifiers of the packages.
+	qualifiers map[string]string
+	// used holds the qualifiers referenced by the expressions built so far.
+	used map[string]bool
+}
+
+func newOracle(decls map[string][]*decl.Validator, imports []ifacetool.Import) *oracle {
+	qualifiers := make(map[string]string)
+	for _, i := range imports {
+		name := i.Alias
+		if name == "" {
+			name = importName(i.Path)
+		}
+		qualifiers[i.Path] = name
+	}
+	return &oracle{decls: decls, qualifiers: qualifiers, used: make(map[string]bool)}
+}
+
+// Expr returns the Go boolean expression of the schema expression s, which
+// validates the param name of type typ.
+func (o *oracle) Expr(name string, typ types.Type, s string) (string, error) {
+	e, err := parser.ParseExpr(s)
+	if err != nil {
+		return "", fmt.Errorf("cannot interpret %q: %v", s, err)
+	}
+	return o.expr(name, typ, e)
+}
+
+func (o *oracle) expr(name string, typ types.Type, e ast.Expr) (string, error) {
+	switch x := e.(type) {
+	case *ast.ParenExpr:
+		return o.expr(name, typ, x.X)
+	case *ast.UnaryExpr:
+		if x.Op != token.NOT {
+			break
+		}
+		inner, err := o.expr(name, typ, x.X)
+		if err != nil {
+			return "", err
+		}
+		return "!(" + inner + ")", nil
+	case *ast.BinaryExpr:
+		if x.Op != token.LAND && x.Op != token.LOR {
+			break
+		}
+		left, err := o.expr(name, typ, x.X)
+		if err != nil {
+			return "", err
+		}
+		right, err := o.expr(name, typ, x.Y)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("(%s %s %s)", left, x.Op, right), nil
+	case *ast.Ident:
+		return o.leaf(name, typ, x.Name, nil)
+	case *ast.CallExpr:
+		if sel, ok := x.Fun.(*ast.SelectorExpr); ok && (sel.Sel.Name == "msg" || sel.Sel.Name == "code") {
+			// The modifiers (e.g. `.msg("...")`) do not affect the validity.
+			return o.expr(name, typ, sel.X)
+		}
+		if ident, ok := x.Fun.(*ast.Ident); ok {
+			var args []string
+			for _, a := range x.Args {
+				args = append(args, exprString(a))
+			}
+			return o.leaf(name, typ, ident.Name, args)
+		}
+	}
+	return "", fmt.Errorf("cannot interpret %s", exprString(e))
+}
+
+// leaf returns the Go boolean expression of the builtin validator alias,
+// whose arguments are args, for the param name of type typ.
+func (o *oracle) leaf(name string, typ types.Type, alias string, args []string) (string, error) {
+	for _, d := range o.decls[alias] {
+		if !d.AllowedTypes.Allow(typ) {
+			continue
+		}
+		if d.Import != validatingPath && d.Import != vextPath {
+			return "", fmt.Errorf("cannot interpret the custom validator `%s`", alias)
+		}
+		break
+	}
+
+	_, isBytes := typ.(*types.Slice)
+	nargs := func(min, max int) error {
+		if len(args) < min || len(args) > max {
+			return fmt.Errorf("wrong number of arguments for validator %q", alias)
+		}
+		return nil
+	}
+	// between returns the expression checking that n is in [args[0], args[1]].
+	between := func(n string) (string, error) {
+		if err := nargs(0, 2); err != nil {
+			return "", err
+		}
+		conds := []string{"true"}
+		if len(args) > 0 {
+			conds = []string{n + " >= " + args[0]}
+		}
+		if len(args) > 1 {
+			conds = append(conds, n+" <= "+args[1])
+		}
+		return "(" + strings.Join(conds, " && ") + ")", nil
+	}
+	// compare returns the expression of the comparison with the argument,
+	// which fails if the opposite comparison (i.e. op) holds.
+	compare := func(op string) (string, error) {
+		if err := nargs(1, 1); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("!(%s %s %s)", name, op, args[0]), nil
+	}
+	// member returns the expression checking that the param equals one of
+	// the arguments.
+	member := func() string {
+		if len(args) == 0 {
+			return "false"
+		}
+		var conds []string
+		for _, a := range args {
+			conds = append(conds, name+" == "+a)
+		}
+		return "(" + strings.Join(conds, " || ") + ")"
+	}
+
+	switch alias {
+	case "nonzero", "zero":
+		if isBytes {
+			break
+		}
+		if err := nargs(0, 0); err != nil {
+			return "", err
+		}
+		op := "!="
+		if alias == "zero" {
+			op = "=="
+		}
+		return fmt.Sprintf("%s %s %s", name, op, zeroLiteral(typ)), nil
+	case "len":
+		return between(fmt.Sprintf("len(%s)", name))
+	case "runecnt":
+		if isBytes {
+			return between(fmt.Sprintf("%s.RuneCount(%s)", o.qualify("unicode/utf8"), name))
+		}
+		return between(fmt.Sprintf("%s.RuneCountInString(%s)", o.qualify("unicode/utf8"), name))
+	case "eq":
+		return compare("!=")
+	case "ne":
+		return compare("==")
+	case "gt":
+		return compare("<=")
+	case "gte":
+		return compare("<")
+	case "lt":
+		return compare(">=")
+	case "lte":
+		return compare(">")
+	case "xrange":
+		if err := nargs(2, 2); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("!(%s < %s || %s > %s)", name, args[0], name, args[1]), nil
+	case "in":
+		return member(), nil
+	case "nin":
+		return "!" + member(), nil
+	case "match":
+		if err := nargs(1, 1); err != nil {
+			return "", err
+		}
+		method := "MatchString"
+		if isBytes {
+			method = "Match"
+		}
+		return fmt.Sprintf("%s.MustCompile(%s).%s(%s)", o.qualify("regexp"), args[0], method, name), nil
+	case "email":
+		if err := nargs(0, 0); err != nil {
+			return "", err
+		}
+		v := o.qualify(validatingPath)
+		return fmt.Sprintf("%s.Validate(%s.Value(%s, %s.Email())) == nil", v, v, name, o.qualify(vextPath)), nil
+	case "ip":
+		if err := nargs(0, 0); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s.ParseIP(%s) != nil", o.qualify("net"), name), nil
+	case "time":
+		if err := nargs(1, 1); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("func() bool { _, err := %s.Parse(%s, %s); return err == nil }()", o.qualify("time"), args[0], name), nil
+	}
+	return "", fmt.Errorf("cannot interpret validator `%s` on type %s", alias, typ)
+}
+
+// qualify returns the qualifier of the package path, and records it as used.
+func (o *oracle) qualify(path string) string {
+	q, ok := o.qualifiers[path]
+	if !ok {
+		q = importName(path)
+	}
+	o.used[q] = true
+	return q
+}
+
+// Shadowed reports whether the qualifiers referenced by the expressions are
+// shadowed by the variable name.
+func (o *oracle) Shadowed(name string) bool {
+	return o.used[name]
+}
+
+// zeroLiteral returns the Go literal of the zero value of the basic type typ.
+func zeroLiteral(typ types.Type) string {
+	b, _ := typ.Underlying().(*types.Basic)
+	switch {
+	case b == nil:
+		return "nil"
+	case b.Info()&types.IsString != 0:
+		return `""`
+	case b.Info()&types.IsBoolean != 0:
+		return "false"
+	default:
+		return "0"
+	}
+}
+
+// exprString returns the source of the expression e.
+func exprString(e ast.Expr) string {
+	var buf strings.Builder
+	_ = printer.Fprint(&buf, token.NewFileSet(), e)
+	return buf.String()
+}
@@ -0,0 +1,134 @@
+package validate
+
+import (
+	"go/types"
+	"testing"
+
+	"github.com/protogodev/protogo/parser/ifacetool"
+	"github.com/protogodev/validate/decl"
+)
+
+func TestOracle_Expr(t *testing.T) {
+	str := types.Typ[types.String]
+	bytes := types.NewSlice(types.Universe.Lookup("byte").Type())
+	imports := []ifacetool.Import{
+		{Alias: "v", Path: validatingPath},
+		{Alias: "vext2", Path: vextPath},
+	}
+
+	tests := []struct {
+		name       string
+		inType     types.Type
+		inExpr     string
+		inDecls    map[string][]*decl.Validator
+		want       string
+		wantErrStr string
+	}{
+		{
+			name:   "logic",
+			inType: types.Typ[types.Int],
+			inExpr: "!(gt(0) && lte(10)) || eq(min)",
+			want:   "(!((!(x <= 0) && !(x > 10))) || !(x != min))",
+		},
+		{
+			name:   "modifiers",
+			inType: types.Typ[types.Bool],
+			inExpr: `nonzero.msg("bad").code("E1")`,
+			want:   "x != false",
+		},
+		{
+			name:   "default arguments",
+			inType: str,
+			inExpr: "len(1) && runecnt()",
+			want:   "((len(x) >= 1) && (true))",
+		},
+		{
+			name:   "bytes",
+			inType: bytes,
+			inExpr: "runecnt(1, 2) && match(`^a`)",
+			want:   "((utf8.RuneCount(x) >= 1 && utf8.RuneCount(x) <= 2) && regexp.MustCompile(`^a`).Match(x))",
+		},
+		{
+			name:   "members",
+			inType: str,
+			inExpr: `in("a", "b") && nin()`,
+			want:   `((x == "a" || x == "b") && !false)`,
+		},
+		{
+			name:   "strings",
+			inType: str,
+			inExpr: `email || ip || time("2006-01-02") || xrange("a", "c")`,
+			want:   `(((v.Validate(v.Value(x, vext2.Email())) == nil || net.ParseIP(x) != nil) || func() bool { _, err := time.Parse("2006-01-02", x); return err == nil }()) || !(x < "a" || x > "c"))`,
+		},
+		{
+			name:       "custom",
+			inType:     str,
+			inExpr:     "uuid",
+			inDecls:    map[string][]*decl.Validator{"uuid": {{Import: "example.com/uuid", Alias: "uuid", AllowedTypes: decl.Types{"string"}}}},
+			wantErrStr: "cannot interpret the custom validator `uuid`",
+		},
+		{
+			name:   "overridden",
+			inType: str,
+			inExpr: "len(1, 10)",
+			inDecls: map[string][]*decl.Validator{"len": {
+				{Import: "example.com/strs", Alias: "len", AllowedTypes: decl.Types{"string"}},
+				{Import: validatingPath, Alias: "len", AllowedTypes: decl.Types{"string"}},
+			}},
+			wantErrStr: "cannot interpret the custom validator `len`",
+		},
+		{
+			name:       "unsupported type",
+			inType:     bytes,
+			inExpr:     "nonzero",
+			wantErrStr: "cannot interpret validator `nonzero` on type []byte",
+		},
+		{
+			name:       "unsupported operator",
+			inType:     types.Typ[types.Int],
+			inExpr:     "gt(0) == lt(10)",
+			wantErrStr: "cannot interpret gt(0) == lt(10)",
+		},
+		{
+			name:       "named arguments",
+			inType:     str,
+			inExpr:     "len(max: 10)",
+			wantErrStr: `cannot interpret "len(max: 10)": 1:8: missing ',' in argument list`,
+		},
+		{
+			name:       "wrong number of arguments",
+			inType:     types.Typ[types.Int],
+			inExpr:     "gt(0, 1)",
+			wantErrStr: `wrong number of arguments for validator "gt"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newOracle(tt.inDecls, imports).Expr("x", tt.inType, tt.inExpr)
+			if err != nil {
+				if err.Error() != tt.wantErrStr {
+					t.Fatalf("Err: got (%#v), want (%#v)", err.Error(), tt.wantErrStr)
+				}
+				return
+			}
+			if tt.wantErrStr != "" {
+				t.Fatalf("Err: got (%#v), want (%#v)", "", tt.wantErrStr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Expr: got (%s), want (%s)", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOracle_Shadowed(t *testing.T) {
+	o := newOracle(nil, nil)
+	if _, err := o.Expr("x", types.Typ[types.String], "runecnt(1, 10)"); err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+	if !o.Shadowed("utf8") || o.Shadowed("regexp") {
+		t.Errorf("Shadowed: got (%v, %v), want (true, false)", o.Shadowed("utf8"), o.Shadowed("regexp"))
+	}
+}
@@ -0,0 +1,198 @@
+package runtime
+
+import (
+	"reflect"
+
+	v "github.com/RussellLuo/validating/v3"
+	"github.com/RussellLuo/vext"
+	"github.com/protogodev/validate/vtime"
+)
+
+// builtinFuncs are the Go functions of the builtin validators, keyed by the
+// entries in the builtin declarations (see decl.BuiltinDecls).
+var builtinFuncs = map[string]interface{}{
+	"vtime.Nonzero": vtime.Nonzero,
+	"vtime.Zero":    vtime.Zero,
+	"v.Nonzero":     Generic(nonzero),
+	"v.Zero":        Generic(zero),
+	"v.LenString":   v.LenString,
+	"v.LenSlice":    Generic(lenSlice),
+	"v.RuneCount":   v.RuneCount,
+	"v.Eq":          Generic(eq),
+	"v.Ne":          Generic(ne),
+	"v.Gt":          Generic(gt),
+	"v.Gte":         Generic(gte),
+	"v.Lt":          Generic(lt),
+	"v.Lte":         Generic(lte),
+	"v.Range":       Generic(xrange),
+	"v.In":          Generic(in),
+	"v.Nin":         Generic(nin),
+	"v.Match":       v.Match,
+	"vext.Email":    vext.Email,
+	"vext.IP":       vext.IP,
+	"vext.Time":     vext.Time,
+	"vtime.After":   vtime.After,
+	"vtime.Before":  vtime.Before,
+	"vtime.Within":  vtime.Within,
+	"vtime.Future":  vtime.Future,
+	"vtime.Past":    vtime.Past,
+}
+
+// check creates a leaf validator, which will succeed when the field's value
+// is of type typ (as the type argument of the generic validator name) and
+// satisfies valid.
+func check(typ reflect.Type, name, message string, valid func(value reflect.Value) bool) (mv *v.MessageValidator) {
+	mv = &v.MessageValidator{
+		Message: message,
+		Validator: v.Func(func(field *v.Field) v.Errors {
+			value, ok := typeAssert(field.Value, typ)
+			if !ok {
+				return v.NewUnsupportedErrors(field, name)
+			}
+
+			if !valid(value) {
+				return v.NewErrors(field.Name, v.ErrInvalid, mv.Message)
+			}
+			return nil
+		}),
+	}
+	return
+}
+
+// typeAssert asserts that x is of type typ, just like `x.(T)` where T is
+// typ, and returns the value of x as typ.
+func typeAssert(x interface{}, typ reflect.Type) (reflect.Value, bool) {
+	value := reflect.ValueOf(x)
+	if !value.IsValid() {
+		// Type assertions on nil always fail.
+		return value, false
+	}
+	if typ.Kind() != reflect.Interface {
+		return value, value.Type() == typ
+	}
+	if !value.Type().Implements(typ) {
+		return value, false
+	}
+	iface := reflect.New(typ).Elem()
+	iface.Set(value)
+	return iface, true
+}
+
+func nonzero(typ reflect.Type, args []reflect.Value) v.Validator {
+	return check(typ, "Nonzero", "is zero valued", func(value reflect.Value) bool {
+		return !equal(value, reflect.Zero(typ))
+	})
+}
+
+func zero(typ reflect.Type, args []reflect.Value) v.Validator {
+	return check(typ, "Zero", "is nonzero", func(value reflect.Value) bool {
+		return equal(value, reflect.Zero(typ))
+	})
+}
+
+func lenSlice(typ reflect.Type, args []reflect.Value) v.Validator {
+	min, max := int(args[0].Int()), int(args[1].Int())
+	return check(typ, "LenSlice", "has an invalid length", func(value reflect.Value) bool {
+		l := value.Len()
+		return l >= min && l <= max
+	})
+}
+
+func eq(typ reflect.Type, args []reflect.Value) v.Validator {
+	return check(typ, "Eq", "does not equal the given value", func(value reflect.Value) bool {
+		return equal(value, args[0])
+	})
+}
+
+func ne(typ reflect.Type, args []reflect.Value) v.Validator {
+	return check(typ, "Ne", "equals the given value", func(value reflect.Value) bool {
+		return !equal(value, args[0])
+	})
+}
+
+func gt(typ reflect.Type, args []reflect.Value) v.Validator {
+	return check(typ, "Gt", "is lower than or equal to the given value", func(value reflect.Value) bool {
+		return compare(value, ">", args[0])
+	})
+}
+
+func gte(typ reflect.Type, args []reflect.Value) v.Validator {
+	return check(typ, "Gte", "is lower than the given value", func(value reflect.Value) bool {
+		return compare(value, ">=", args[0])
+	})
+}
+
+func lt(typ reflect.Type, args []reflect.Value) v.Validator {
+	return check(typ, "Lt", "is greater than or equal to the given value", func(value reflect.Value) bool {
+		return compare(value, "<", args[0])
+	})
+}
+
+func lte(typ reflect.Type, args []reflect.Value) v.Validator {
+	return check(typ, "Lte", "is greater than the given value", func(value reflect.Value) bool {
+		return compare(value, "<=", args[0])
+	})
+}
+
+func xrange(typ reflect.Type, args []reflect.Value) v.Validator {
+	return check(typ, "Range", "is not between the given range", func(value reflect.Value) bool {
+		return compare(value, ">=", args[0]) && compare(value, "<=", args[1])
+	})
+}
+
+func in(typ reflect.Type, args []reflect.Value) v.Validator {
+	return check(typ, "In", "is not one of the given values", func(value reflect.Value) bool {
+		return contains(args, value)
+	})
+}
+
+func nin(typ reflect.Type, args []reflect.Value) v.Validator {
+	return check(typ, "Nin", "is one of the given values", func(value reflect.Value) bool {
+		return !contains(args, value)
+	})
+}
+
+// equal reports whether x and y, which are of the same comparable type, are
+// equal by `==`.
+func equal(x, y reflect.Value) bool {
+	return x.Interface() == y.Interface()
+}
+
+func contains(values []reflect.Value, x reflect.Value) bool {
+	for _, value := range values {
+		if equal(x, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// compare reports whether `x op y` holds, where x and y are of the same
+// ordered type.
+func compare(x reflect.Value, op string, y reflect.Value) bool {
+	switch x.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return ordered(x.Int(), op, y.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return ordered(x.Uint(), op, y.Uint())
+	case reflect.Float32, reflect.Float64:
+		return ordered(x.Float(), op, y.Float())
+	case reflect.String:
+		return ordered(x.String(), op, y.String())
+	}
+	return false
+}
+
+func ordered[T int64 | uint64 | float64 | string](x T, op string, y T) bool {
+	switch op {
+	case "<":
+		return x < y
+	case "<=":
+		return x <= y
+	case ">":
+		return x > y
+	case ">=":
+		return x >= y
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package runtime
+
+import (
+	"reflect"
+	"testing"
+
+	v "github.com/RussellLuo/validating/v3"
+)
+
+func TestBuiltin_UnsupportedType(t *testing.T) {
+	intType := reflect.TypeOf(0)
+	tests := []struct {
+		name      string
+		validator v.Validator
+		wantErr   string
+	}{
+		{
+			name:      "nonzero",
+			validator: nonzero(intType, nil),
+			wantErr:   "UNSUPPORTED(cannot use validator `Nonzero` on type string)",
+		},
+		{
+			name:      "zero",
+			validator: zero(intType, nil),
+			wantErr:   "UNSUPPORTED(cannot use validator `Zero` on type string)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotErr string
+			if errs := v.Validate(v.Value("a", tt.validator)); errs != nil {
+				gotErr = errs.Error()
+			}
+			if gotErr != tt.wantErr {
+				t.Errorf("Err: got (%#v), want (%#v)", gotErr, tt.wantErr)
+			}
+		})
+	}
+}
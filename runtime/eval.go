@@ -0,0 +1,183 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"go/constant"
+	"reflect"
+	"regexp"
+	"time"
+
+	"github.com/protogodev/validate/decl"
+	"github.com/protogodev/validate/expr"
+)
+
+// kindTypes maps the declared argument kinds to the types.
+var kindTypes = map[string]reflect.Type{
+	"int":      reflect.TypeOf(int(0)),
+	"uint":     reflect.TypeOf(uint(0)),
+	"float":    reflect.TypeOf(float64(0)),
+	"string":   reflect.TypeOf(""),
+	"bool":     reflect.TypeOf(false),
+	"time":     reflect.TypeOf(time.Time{}),
+	"duration": reflect.TypeOf(time.Duration(0)),
+	"any":      reflect.TypeOf((*interface{})(nil)).Elem(),
+}
+
+var regexpType = reflect.TypeOf((*regexp.Regexp)(nil))
+
+// argType returns the wanted type of the i-th argument of the validator,
+// which is declared by d and created by fn, when validating the values of
+// type typ.
+func argType(d *decl.Validator, fn reflect.Value, typ reflect.Type, i int) reflect.Type {
+	if _, ok := fn.Interface().(Generic); ok {
+		if len(d.Args) == 0 {
+			// Only the number of arguments is declared, which are assumed to
+			// be the type arguments.
+			return typ
+		}
+		if i >= len(d.Args) {
+			i = len(d.Args) - 1 // The variadic argument.
+		}
+		if kind := d.Args[i].Kind; kind != "T" {
+			return kindTypes[kind]
+		}
+		return typ
+	}
+
+	t := fn.Type()
+	if d.Ctx {
+		i++
+	}
+	if t.IsVariadic() && i >= t.NumIn()-1 {
+		return t.In(t.NumIn() - 1).Elem()
+	}
+	return t.In(i)
+}
+
+// arg is an evaluated argument, which either holds a value, or references
+// a variable in scope.
+type arg struct {
+	want  reflect.Type
+	value reflect.Value
+	ref   string
+}
+
+// evalArg evaluates the argument s, whose value must be of type want.
+func evalArg(s string, want reflect.Type, scope map[string]reflect.Type) (arg, error) {
+	if _, ok := scope[s]; ok {
+		return arg{want: want, ref: s}, nil
+	}
+
+	var value reflect.Value
+	var err error
+	if t, ok := expr.EvalTime(s); ok {
+		value, err = convert(reflect.ValueOf(t), want)
+	} else if c, ok := expr.EvalConst(s); ok {
+		value, err = constValue(c, want)
+	} else {
+		err = errors.New("cannot be evaluated at runtime (want a literal, a constant expression or a variable in scope)")
+	}
+	if err != nil {
+		return arg{}, err
+	}
+	return arg{want: want, value: value}, nil
+}
+
+// resolve returns the value of a, in which the referenced variable is
+// resolved by env.
+func (a arg) resolve(env map[string]interface{}) (reflect.Value, error) {
+	if a.ref == "" {
+		return a.value, nil
+	}
+
+	x, ok := env[a.ref]
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("missing the value of %s", a.ref)
+	}
+	if x == nil {
+		return reflect.Zero(a.want), nil
+	}
+	return convert(reflect.ValueOf(x), a.want)
+}
+
+// convert converts value to type want, as the Go compiler does when passing
+// value as an argument of type want.
+func convert(value reflect.Value, want reflect.Type) (reflect.Value, error) {
+	if want == regexpType && value.Kind() == reflect.String {
+		// The pattern of `match`.
+		re, err := regexp.Compile(value.String())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(re), nil
+	}
+
+	switch t := value.Type(); {
+	case t.AssignableTo(want):
+		v := reflect.New(want).Elem()
+		v.Set(value)
+		return v, nil
+	case t.ConvertibleTo(want):
+		return value.Convert(want), nil
+	}
+	return reflect.Value{}, fmt.Errorf("cannot use %v (type %s) as %s", value, value.Type(), want)
+}
+
+// constValue converts the constant c to a value of type want.
+func constValue(c constant.Value, want reflect.Type) (reflect.Value, error) {
+	if want.Kind() == reflect.Interface {
+		// Use the default type of the untyped constant.
+		switch c.Kind() {
+		case constant.Bool:
+			return convert(reflect.ValueOf(constant.BoolVal(c)), want)
+		case constant.String:
+			return convert(reflect.ValueOf(constant.StringVal(c)), want)
+		case constant.Int:
+			if n, ok := constant.Int64Val(c); ok {
+				return convert(reflect.ValueOf(int(n)), want)
+			}
+		case constant.Float:
+			f, _ := constant.Float64Val(c)
+			return convert(reflect.ValueOf(f), want)
+		}
+		return reflect.Value{}, fmt.Errorf("cannot use %s as %s", c, want)
+	}
+
+	value := reflect.New(want).Elem()
+	switch want.Kind() {
+	case reflect.Bool:
+		if c.Kind() == constant.Bool {
+			value.SetBool(constant.BoolVal(c))
+			return value, nil
+		}
+	case reflect.String:
+		if c.Kind() == constant.String {
+			value.SetString(constant.StringVal(c))
+			return value, nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, ok := constant.Int64Val(constant.ToInt(c)); ok && !value.OverflowInt(n) {
+			value.SetInt(n)
+			return value, nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if n, ok := constant.Uint64Val(constant.ToInt(c)); ok && !value.OverflowUint(n) {
+			value.SetUint(n)
+			return value, nil
+		}
+	case reflect.Float32, reflect.Float64:
+		if f := constant.ToFloat(c); f.Kind() == constant.Int || f.Kind() == constant.Float {
+			x, _ := constant.Float64Val(f)
+			if !value.OverflowFloat(x) {
+				value.SetFloat(x)
+				return value, nil
+			}
+		}
+	case reflect.Pointer:
+		if want == regexpType && c.Kind() == constant.String {
+			return convert(reflect.ValueOf(constant.StringVal(c)), want)
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("cannot use %s as %s", c, want)
+}
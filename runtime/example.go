@@ -0,0 +1,696 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"reflect"
+	"regexp/syntax"
+	"sort"
+	"strings"
+	"time"
+
+	v "github.com/RussellLuo/validating/v3"
+	"github.com/protogodev/validate/decl"
+	"github.com/protogodev/validate/expr"
+	"github.com/protogodev/validate/vtime"
+)
+
+// maxExampleLen is the maximum length of the synthesized strings (or
+// slices), which skips the unreachable lengths such as the default maximum
+// of `len`.
+const maxExampleLen = 1 << 12
+
+// exampleTime is the time used for the time-related validators, which need
+// no specific time (e.g. `time("2006-01-02")`).
+var exampleTime = time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+
+// ErrUnsatisfiable is returned by Example and ExampleParams, when the
+// constraints are found unsatisfiable (e.g. `xrange(10, 1)`).
+var ErrUnsatisfiable = errors.New("unsatisfiable")
+
+// exampleOnly returns the Go function of the validator declared by d, which
+// is only satisfied by its example.
+func exampleOnly(d *decl.Validator) Generic {
+	return func(typ reflect.Type, args []reflect.Value) v.Validator {
+		example, ok := declExample(d, typ)
+		return check(typ, d.Name, "does not equal the example of the declared validator", func(value reflect.Value) bool {
+			return ok && equal(value, example)
+		})
+	}
+}
+
+// declExample returns the example of the validator declared by d, as a
+// value of type typ.
+func declExample(d *decl.Validator, typ reflect.Type) (reflect.Value, bool) {
+	c, ok := expr.EvalConst(d.Example)
+	if !ok {
+		return reflect.Value{}, false
+	}
+	value, err := constValue(c, typ)
+	return value, err == nil
+}
+
+// Example synthesizes a value of type typ, which satisfies the schema
+// expression s referencing no other variables, e.g. a string matching the
+// pattern of `match`, or a number within `xrange`. It returns an error
+// wrapping ErrUnsatisfiable if the constraints are found unsatisfiable, or
+// another error if no such value can be found.
+func (r *Registry) Example(s string, typ reflect.Type) (interface{}, error) {
+	t, err := r.newTarget("value", s, typ, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	env := map[string]interface{}{"value": reflect.Zero(typ).Interface()}
+	value, err := t.example(context.Background(), env)
+	if err != nil {
+		return nil, err
+	}
+	return value.Interface(), nil
+}
+
+// ExampleParams synthesizes the params (e.g. the params of a method), whose
+// types are given by paramTypes, satisfying rules (just like ValidateParams).
+// The params without rules are zero values.
+func (r *Registry) ExampleParams(ctx context.Context, rules map[string]string, paramTypes map[string]reflect.Type) (map[string]interface{}, error) {
+	env := make(map[string]interface{})
+	for name, typ := range paramTypes {
+		env[name] = reflect.Zero(typ).Interface()
+	}
+
+	// Synthesize the params in order, which makes the results deterministic.
+	names := make([]string, 0, len(rules))
+	for name := range rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var targets []*target
+	for _, name := range names {
+		typ, ok := paramTypes[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown param %q", name)
+		}
+		t, err := r.newTarget(name, rules[name], typ, paramTypes)
+		if err != nil {
+			return nil, fmt.Errorf("param %q: %w", name, err)
+		}
+		targets = append(targets, t)
+	}
+
+	// Since the rules may reference each other (e.g. `gt(min)`), repeat
+	// until all the params are valid.
+	for i := 0; i <= len(targets); i++ {
+		done := true
+		for _, t := range targets {
+			ok, err := t.valid(ctx, env, reflect.ValueOf(env[t.name]))
+			if err != nil {
+				return nil, fmt.Errorf("param %q: %w", t.name, err)
+			}
+			if ok {
+				continue
+			}
+
+			done = false
+			value, err := t.example(ctx, env)
+			if err != nil {
+				return nil, fmt.Errorf("param %q: %w", t.name, err)
+			}
+			env[t.name] = value.Interface()
+		}
+		if done {
+			return env, nil
+		}
+	}
+	return nil, fmt.Errorf("cannot find the params satisfying all the rules")
+}
+
+// target is a variable, whose value is to be synthesized.
+type target struct {
+	name      string
+	s         string
+	typ       reflect.Type
+	validator expr.Validator
+	rule      *Rule
+}
+
+func (r *Registry) newTarget(name, s string, typ reflect.Type, scope map[string]reflect.Type) (*target, error) {
+	validator, err := r.bind(s, typ, scope)
+	if err != nil {
+		return nil, err
+	}
+	build, err := r.compile(validator, typ, scope)
+	if err != nil {
+		return nil, err
+	}
+	return &target{
+		name:      name,
+		s:         s,
+		typ:       typ,
+		validator: validator,
+		rule:      &Rule{build: build},
+	}, nil
+}
+
+// valid reports whether value satisfies the rule of t, in which the other
+// variables are resolved by env.
+func (t *target) valid(ctx context.Context, env map[string]interface{}, value reflect.Value) (bool, error) {
+	validator, err := t.rule.Validator(ctx, env)
+	if err != nil {
+		return false, err
+	}
+	return len(v.Validate(v.Value(value.Interface(), validator))) == 0, nil
+}
+
+// example returns the first candidate value satisfying the rule of t.
+func (t *target) example(ctx context.Context, env map[string]interface{}) (reflect.Value, error) {
+	leaves := conjuncts(t.validator)
+	if err := checkBounds(leaves); err != nil {
+		return reflect.Value{}, err
+	}
+
+	g := &candidateGen{env: env}
+	for _, value := range g.candidates(t.validator, t.typ) {
+		ok, err := t.valid(ctx, env, value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if ok {
+			return value, nil
+		}
+	}
+
+	// All the values allowed by `eq` or `in` have been tried, which proves
+	// the constraints unsatisfiable if they reference no other variables.
+	var allowed *expr.LeafValidator
+	for _, x := range leaves {
+		if !constArgs(x) {
+			allowed = nil
+			break
+		}
+		if entry := x.MatchedDecl().Entry(); entry == "v.Eq" || entry == "v.In" {
+			allowed = x
+		}
+	}
+	if allowed != nil {
+		return reflect.Value{}, fmt.Errorf("%w: no value allowed by `%s` satisfies `%s`", ErrUnsatisfiable, allowed.Name, t.s)
+	}
+	return reflect.Value{}, fmt.Errorf("cannot find a value satisfying `%s`", t.s)
+}
+
+// conjuncts returns the leaf validators, which must be all satisfied by the
+// value (i.e. the operands of `&&`).
+func conjuncts(validator expr.Validator) []*expr.LeafValidator {
+	switch x := validator.(type) {
+	case *expr.PointerValidator:
+		_, isPointer := x.Param.Type.Underlying().(*types.Pointer)
+		if x.Inner == nil || (isPointer && x.Presence != "required") {
+			// A nil pointer satisfies the validator.
+			return nil
+		}
+		return conjuncts(x.Inner)
+	case *expr.LogicValidator:
+		if x.Name == "&&" {
+			return append(conjuncts(x.Left), conjuncts(x.Right)...)
+		}
+	case *expr.LeafValidator:
+		if x.Name != "_" && x.MatchedDecl() != nil {
+			return []*expr.LeafValidator{x}
+		}
+	}
+	return nil
+}
+
+// constArgs reports whether the arguments of x are all constants.
+func constArgs(x *expr.LeafValidator) bool {
+	for _, arg := range x.Args {
+		if _, ok := expr.EvalConst(arg); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// bound is a lower or upper bound of the values.
+type bound struct {
+	c      constant.Value
+	strict bool
+}
+
+// checkBounds reports an error wrapping ErrUnsatisfiable if the constant
+// bounds (e.g. of `gt` and `lt`) or the constant lengths (e.g. of `len`)
+// in leaves conflict.
+func checkBounds(leaves []*expr.LeafValidator) error {
+	var lower, upper *bound
+	// tighten replaces *b with the bound of c, if it is tighter (i.e. `c op
+	// (*b).c` holds).
+	tighten := func(b **bound, c constant.Value, strict bool, op token.Token) {
+		if *b != nil {
+			if !comparableConsts((*b).c, c) {
+				return
+			}
+			if !constant.Compare(c, op, (*b).c) && !(strict && constant.Compare(c, token.EQL, (*b).c)) {
+				return
+			}
+		}
+		*b = &bound{c: c, strict: strict}
+	}
+	lengths := make(map[string][2]int64) // Keyed by the validator names.
+
+	for _, x := range leaves {
+		var args []constant.Value
+		for _, arg := range x.Args {
+			c, ok := expr.EvalConst(arg)
+			if !ok {
+				break
+			}
+			args = append(args, c)
+		}
+		if len(args) != len(x.Args) {
+			// Some arguments reference other variables.
+			continue
+		}
+
+		switch x.MatchedDecl().Entry() {
+		case "v.LenString", "v.LenSlice", "v.RuneCount":
+			min, _ := constant.Int64Val(args[0])
+			max, _ := constant.Int64Val(args[1])
+			if l, ok := lengths[x.Name]; ok {
+				if l[0] > min {
+					min = l[0]
+				}
+				if l[1] < max {
+					max = l[1]
+				}
+			}
+			if min > max {
+				return fmt.Errorf("%w: no length of `%s` is within [%d, %d]", ErrUnsatisfiable, x.Name, min, max)
+			}
+			lengths[x.Name] = [2]int64{min, max}
+			continue
+		case "v.Eq":
+			tighten(&lower, args[0], false, token.GTR)
+			tighten(&upper, args[0], false, token.LSS)
+		case "v.Gt":
+			tighten(&lower, args[0], true, token.GTR)
+		case "v.Gte":
+			tighten(&lower, args[0], false, token.GTR)
+		case "v.Lt":
+			tighten(&upper, args[0], true, token.LSS)
+		case "v.Lte":
+			tighten(&upper, args[0], false, token.LSS)
+		case "v.Range":
+			tighten(&lower, args[0], false, token.GTR)
+			tighten(&upper, args[1], false, token.LSS)
+		default:
+			continue
+		}
+
+		if lower == nil || upper == nil || !comparableConsts(lower.c, upper.c) {
+			continue
+		}
+		if constant.Compare(lower.c, token.GTR, upper.c) || (constant.Compare(lower.c, token.EQL, upper.c) && (lower.strict || upper.strict)) {
+			open, close := "[", "]"
+			if lower.strict {
+				open = "("
+			}
+			if upper.strict {
+				close = ")"
+			}
+			return fmt.Errorf("%w: no value is within %s%s, %s%s", ErrUnsatisfiable, open, lower.c.ExactString(), upper.c.ExactString(), close)
+		}
+	}
+
+	return nil
+}
+
+// comparableConsts reports whether the constants x and y are ordered, and
+// can be compared with each other.
+func comparableConsts(x, y constant.Value) bool {
+	numeric := func(c constant.Value) bool {
+		return c.Kind() == constant.Int || c.Kind() == constant.Float
+	}
+	if x.Kind() == constant.String {
+		return y.Kind() == constant.String
+	}
+	return numeric(x) && numeric(y)
+}
+
+// candidateGen generates the candidate values for the validators, in which
+// the arguments referencing other variables are resolved by env.
+type candidateGen struct {
+	env map[string]interface{}
+}
+
+// constArg evaluates the argument of a leaf validator to a constant.
+func (g *candidateGen) constArg(arg string) (constant.Value, bool) {
+	if x, ok := g.env[arg]; ok {
+		value := reflect.ValueOf(x)
+		switch value.Kind() {
+		case reflect.Bool:
+			return constant.MakeBool(value.Bool()), true
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return constant.MakeInt64(value.Int()), true
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return constant.MakeUint64(value.Uint()), true
+		case reflect.Float32, reflect.Float64:
+			return constant.MakeFloat64(value.Float()), true
+		case reflect.String:
+			return constant.MakeString(value.String()), true
+		}
+		return nil, false
+	}
+	return expr.EvalConst(arg)
+}
+
+// timeArg evaluates the argument of a leaf validator to a time.
+func (g *candidateGen) timeArg(arg string) (time.Time, bool) {
+	if x, ok := g.env[arg]; ok {
+		t, ok := x.(time.Time)
+		return t, ok
+	}
+	return expr.EvalTime(arg)
+}
+
+// candidates returns the candidate values of type typ for the validator,
+// in which the values more likely to satisfy the validator come first.
+func (g *candidateGen) candidates(validator expr.Validator, typ reflect.Type) (values []reflect.Value) {
+	switch {
+	case typ.Kind() == reflect.Pointer:
+		var inner expr.Validator
+		if x, ok := validator.(*expr.PointerValidator); ok {
+			inner = x.Inner
+		}
+		for _, elem := range g.candidates(inner, typ.Elem()) {
+			ptr := reflect.New(typ.Elem())
+			ptr.Elem().Set(elem)
+			values = append(values, ptr)
+		}
+		return append(values, reflect.Zero(typ))
+
+	case typ.Kind() == reflect.Slice && typ.Elem().Kind() != reflect.Uint8:
+		elems := g.elemCandidates(validator, "each", typ.Elem())
+		for _, elem := range elems {
+			for _, n := range g.lengths(validator) {
+				s := reflect.MakeSlice(typ, int(n), int(n))
+				for i := 0; i < int(n); i++ {
+					s.Index(i).Set(elem)
+				}
+				values = append(values, s)
+			}
+		}
+		return append(values, reflect.MakeSlice(typ, 0, 0), reflect.Zero(typ))
+
+	case typ.Kind() == reflect.Map:
+		keys := g.elemCandidates(validator, "keys", typ.Key())
+		elems := g.elemCandidates(validator, "values", typ.Elem())
+		for _, key := range keys {
+			for _, elem := range elems {
+				m := reflect.MakeMap(typ)
+				m.SetMapIndex(key, elem)
+				values = append(values, m)
+			}
+		}
+		return append(values, reflect.MakeMap(typ), reflect.Zero(typ))
+	}
+
+	var leaves []*expr.LeafValidator
+	collectLeaves(validator, &leaves)
+	add := func(xs ...interface{}) {
+		for _, x := range xs {
+			if value, ok := exampleValue(x, typ); ok {
+				values = append(values, value)
+			}
+		}
+	}
+
+	// The values, which are more likely to satisfy the validator, come first.
+	var consts []constant.Value
+	for _, x := range leaves {
+		d := x.MatchedDecl()
+		switch d.Entry() {
+		case "v.Eq", "v.In":
+			for _, arg := range x.Args {
+				if c, ok := g.constArg(arg); ok {
+					add(c)
+				}
+			}
+		default:
+			if d.Example != "" {
+				if value, ok := declExample(d, typ); ok {
+					values = append(values, value)
+				}
+			}
+		}
+	}
+	for _, x := range leaves {
+		switch x.MatchedDecl().Entry() {
+		case "vext.Email":
+			add("user@example.com")
+		case "vext.IP":
+			add("127.0.0.1")
+		case "vext.Time":
+			if c, ok := g.constArg(x.Args[0]); ok && c.Kind() == constant.String {
+				add(exampleTime.Format(constant.StringVal(c)))
+			}
+		case "v.Match":
+			if c, ok := g.constArg(x.Args[0]); ok && c.Kind() == constant.String {
+				for _, n := range append([]int64{1, 0, 2, 3, 5, 8}, g.lengths(validator)...) {
+					if s, ok := regexpExample(constant.StringVal(c), int(n)); ok {
+						add(s)
+					}
+				}
+			}
+		case "v.LenString", "v.LenSlice", "v.RuneCount":
+		case "v.Gt", "v.Gte", "v.Lt", "v.Lte", "v.Range", "v.Ne", "v.Nin":
+			for _, arg := range x.Args {
+				if c, ok := g.constArg(arg); ok {
+					consts = append(consts, c)
+				}
+			}
+		case "vtime.After":
+			if t, ok := g.timeArg(x.Args[0]); ok {
+				add(t.Add(time.Hour))
+			}
+		case "vtime.Before":
+			if t, ok := g.timeArg(x.Args[0]); ok {
+				add(t.Add(-time.Hour))
+			}
+		case "vtime.Future":
+			add(vtime.Now().Add(24 * time.Hour).Truncate(time.Second))
+		case "vtime.Past", "vtime.Within":
+			add(vtime.Now().Truncate(time.Second))
+		}
+	}
+
+	// The values around (or between) the constant arguments.
+	for i, c := range consts {
+		add(around(c)...)
+		for _, d := range consts[:i] {
+			if c.Kind() != constant.String && d.Kind() != constant.String {
+				add(constant.BinaryOp(constant.BinaryOp(c, token.ADD, d), token.QUO, constant.MakeInt64(2)))
+			}
+		}
+	}
+
+	for _, n := range g.lengths(validator) {
+		add(strings.Repeat("a", int(n)))
+	}
+	add("a", constant.MakeInt64(1), true, exampleTime, reflect.Zero(typ))
+	return values
+}
+
+// elemCandidates returns the candidate elements (or keys, or values), per
+// the validators of the given name (e.g. `each`) in validator.
+func (g *candidateGen) elemCandidates(validator expr.Validator, name string, typ reflect.Type) []reflect.Value {
+	var inner expr.Validator
+	var find func(validator expr.Validator)
+	find = func(validator expr.Validator) {
+		switch x := validator.(type) {
+		case *expr.PointerValidator:
+			find(x.Inner)
+		case *expr.LogicValidator:
+			find(x.Left)
+			find(x.Right)
+		case *expr.ElemValidator:
+			if x.Name == name && inner == nil {
+				inner = x.Inner
+			}
+		}
+	}
+	find(validator)
+	return g.candidates(inner, typ)
+}
+
+// lengths returns the candidate lengths per the length validators in
+// validator, which are `1` if none.
+func (g *candidateGen) lengths(validator expr.Validator) (ns []int64) {
+	var leaves []*expr.LeafValidator
+	collectLeaves(validator, &leaves)
+	for _, x := range leaves {
+		switch x.MatchedDecl().Entry() {
+		case "v.LenString", "v.LenSlice", "v.RuneCount":
+			for _, arg := range x.Args {
+				c, ok := g.constArg(arg)
+				if !ok {
+					continue
+				}
+				if n, ok := constant.Int64Val(c); ok && n > 0 && n <= maxExampleLen {
+					ns = append(ns, n)
+				}
+			}
+		}
+	}
+	return append(ns, 1)
+}
+
+// collectLeaves collects the leaf validators in validator, excluding the
+// ones applied to the elements.
+func collectLeaves(validator expr.Validator, leaves *[]*expr.LeafValidator) {
+	switch x := validator.(type) {
+	case *expr.PointerValidator:
+		collectLeaves(x.Inner, leaves)
+	case *expr.LogicValidator:
+		collectLeaves(x.Left, leaves)
+		collectLeaves(x.Right, leaves)
+	case *expr.LeafValidator:
+		if x.Name != "_" && x.MatchedDecl() != nil {
+			*leaves = append(*leaves, x)
+		}
+	}
+}
+
+// around returns the constants around c, e.g. 10, 11 and 9 for 10.
+func around(c constant.Value) []interface{} {
+	switch c.Kind() {
+	case constant.String:
+		s := constant.StringVal(c)
+		values := []interface{}{s, s + "a"}
+		if s != "" {
+			values = append(values, s[:len(s)-1])
+		}
+		return values
+	case constant.Int, constant.Float:
+		one := constant.MakeInt64(1)
+		return []interface{}{c, constant.BinaryOp(c, token.ADD, one), constant.BinaryOp(c, token.SUB, one)}
+	}
+	return []interface{}{c}
+}
+
+// exampleValue converts x, which is a constant, a Go value or a
+// reflect.Value, into a value of type typ.
+func exampleValue(x interface{}, typ reflect.Type) (reflect.Value, bool) {
+	var value reflect.Value
+	var err error
+	switch x := x.(type) {
+	case constant.Value:
+		if x.Kind() == constant.String && typ.Kind() != reflect.String {
+			value, err = convert(reflect.ValueOf(constant.StringVal(x)), typ)
+		} else {
+			value, err = constValue(x, typ)
+		}
+	case reflect.Value:
+		return x, x.Type() == typ
+	case string:
+		if typ.Kind() != reflect.String && (typ.Kind() != reflect.Slice || typ.Elem().Kind() != reflect.Uint8) {
+			return value, false
+		}
+		value, err = convert(reflect.ValueOf(x), typ)
+	default:
+		value = reflect.ValueOf(x)
+		if value.Type() != typ {
+			return value, false
+		}
+	}
+	return value, err == nil
+}
+
+// regexpExample returns a string matching pattern, in which each unbounded
+// repetition (e.g. `a*` or `a+`) repeats n times if possible.
+func regexpExample(pattern string, n int) (string, bool) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+
+	var b strings.Builder
+	var gen func(re *syntax.Regexp, n int) bool
+	gen = func(re *syntax.Regexp, n int) bool {
+		repeat := func(sub *syntax.Regexp, min, max int) bool {
+			count := n
+			if count < min {
+				count = min
+			}
+			if max >= 0 && count > max {
+				count = max
+			}
+			for i := 0; i < count; i++ {
+				if !gen(sub, n) {
+					return false
+				}
+			}
+			return true
+		}
+
+		switch re.Op {
+		case syntax.OpEmptyMatch, syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText,
+			syntax.OpEndText, syntax.OpWordBoundary, syntax.OpNoWordBoundary:
+		case syntax.OpLiteral:
+			b.WriteString(string(re.Rune))
+		case syntax.OpCharClass:
+			r, ok := classRune(re.Rune)
+			if !ok {
+				return false
+			}
+			b.WriteRune(r)
+		case syntax.OpAnyCharNotNL, syntax.OpAnyChar:
+			b.WriteRune('a')
+		case syntax.OpCapture:
+			return gen(re.Sub[0], n)
+		case syntax.OpStar:
+			return repeat(re.Sub[0], 0, -1)
+		case syntax.OpPlus:
+			return repeat(re.Sub[0], 1, -1)
+		case syntax.OpQuest:
+			return repeat(re.Sub[0], 0, 1)
+		case syntax.OpRepeat:
+			return repeat(re.Sub[0], re.Min, re.Max)
+		case syntax.OpConcat:
+			for _, sub := range re.Sub {
+				if !gen(sub, n) {
+					return false
+				}
+			}
+		case syntax.OpAlternate:
+			return gen(re.Sub[0], n)
+		default:
+			return false
+		}
+		return true
+	}
+
+	if !gen(re, n) {
+		return "", false
+	}
+	return b.String(), true
+}
+
+// classRune returns a rune in the character class, whose ranges are given
+// by pairs of runes. The common letters and digits are preferred.
+func classRune(ranges []rune) (rune, bool) {
+	if len(ranges) == 0 {
+		return 0, false
+	}
+	for _, r := range "aA0_-." {
+		for i := 0; i+1 < len(ranges); i += 2 {
+			if ranges[i] <= r && r <= ranges[i+1] {
+				return r, true
+			}
+		}
+	}
+	return ranges[0], true
+}
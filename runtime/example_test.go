@@ -0,0 +1,221 @@
+package runtime_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/protogodev/validate/decl"
+	"github.com/protogodev/validate/runtime"
+)
+
+func TestRegistry_Example(t *testing.T) {
+	tests := []struct {
+		name    string
+		inStr   string
+		inType  interface{} // The zero value of the type.
+		want    interface{}
+		wantErr string
+	}{
+		{
+			name:   "len and match",
+			inStr:  "len(5, 10) && match(`^[a-z]+$`)",
+			inType: "",
+			want:   "aaaaa",
+		},
+		{
+			name:   "match",
+			inStr:  "match(`^[0-9a-f]{8}-[0-9a-f]{4}$`)",
+			inType: "",
+			want:   "aaaaaaaa-aaaa",
+		},
+		{
+			name:   "xrange",
+			inStr:  "xrange(10, 20)",
+			inType: 0,
+			want:   10,
+		},
+		{
+			name:   "between floats",
+			inStr:  "gt(1.5) && lt(1.6)",
+			inType: 0.0,
+			want:   1.55,
+		},
+		{
+			name:   "in",
+			inStr:  `ne("Y") && in("Y", "N")`,
+			inType: "",
+			want:   "N",
+		},
+		{
+			name:   "email",
+			inStr:  "email",
+			inType: "",
+			want:   "user@example.com",
+		},
+		{
+			name:   "ip",
+			inStr:  "ip",
+			inType: "",
+			want:   "127.0.0.1",
+		},
+		{
+			name:   "time layout",
+			inStr:  `time("2006-01-02")`,
+			inType: "",
+			want:   "2006-01-02",
+		},
+		{
+			name:   "each",
+			inStr:  "len(2, 3) && each(email)",
+			inType: []string(nil),
+			want:   []string{"user@example.com", "user@example.com"},
+		},
+		{
+			name:   "keys and values",
+			inStr:  "keys(len(2, 2)) && values(gt(0))",
+			inType: map[string]int(nil),
+			want:   map[string]int{"aa": 1},
+		},
+		{
+			name:   "required pointer",
+			inStr:  "required && gte(3)",
+			inType: (*int)(nil),
+			want:   func() *int { x := 3; return &x }(),
+		},
+		{
+			name:   "time",
+			inStr:  `after("2024-01-01")`,
+			inType: time.Time{},
+			want:   time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "duration",
+			inStr:  `xrange("1s", "1h30m")`,
+			inType: time.Duration(0),
+			want:   time.Second,
+		},
+		{
+			name:    "unsatisfiable range",
+			inStr:   "xrange(10, 1)",
+			inType:  0,
+			wantErr: "unsatisfiable: no value is within [10, 1]",
+		},
+		{
+			name:    "unsatisfiable bounds",
+			inStr:   "gt(5) && lte(5)",
+			inType:  0,
+			wantErr: "unsatisfiable: no value is within (5, 5]",
+		},
+		{
+			name:    "unsatisfiable lengths",
+			inStr:   "len(5, 10) && len(1, 3)",
+			inType:  "",
+			wantErr: "unsatisfiable: no length of `len` is within [5, 3]",
+		},
+		{
+			name:    "unsatisfiable in",
+			inStr:   `in("a") && nin("a")`,
+			inType:  "",
+			wantErr: "unsatisfiable: no value allowed by `in` satisfies `in(\"a\") && nin(\"a\")`",
+		},
+		{
+			name:    "not found",
+			inStr:   "zero && nonzero",
+			inType:  0,
+			wantErr: "cannot find a value satisfying `zero && nonzero`",
+		},
+	}
+
+	r := runtime.New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.Example(tt.inStr, reflect.TypeOf(tt.inType))
+			var gotErr string
+			if err != nil {
+				gotErr = err.Error()
+			}
+			if gotErr != tt.wantErr {
+				t.Fatalf("Err: got (%#v), want (%#v)", gotErr, tt.wantErr)
+			}
+			if !cmp.Equal(got, tt.want) {
+				diff := cmp.Diff(tt.want, got)
+				t.Errorf("Want - Got: %s", diff)
+			}
+			if err == nil {
+				if err := r.Validate(tt.inStr, got); err != nil {
+					t.Errorf("Validate: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestRegistry_ExampleParams(t *testing.T) {
+	r := runtime.New()
+	got, err := r.ExampleParams(context.Background(), map[string]string{
+		"min":  "gte(1)",
+		"max":  "gt(min)",
+		"name": "len(3, 5)",
+	}, map[string]reflect.Type{
+		"min":   reflect.TypeOf(0),
+		"max":   reflect.TypeOf(0),
+		"name":  reflect.TypeOf(""),
+		"other": reflect.TypeOf(false),
+	})
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+
+	want := map[string]interface{}{
+		"min":   1,
+		"max":   2,
+		"name":  "aaa",
+		"other": false,
+	}
+	if !cmp.Equal(got, want) {
+		diff := cmp.Diff(want, got)
+		t.Errorf("Want - Got: %s", diff)
+	}
+}
+
+func TestRegistry_Declare(t *testing.T) {
+	custom, err := decl.Parse(`package custom
+
+import (
+	"example.com/custom"
+)
+
+var _ = []any{
+	// type=string example="123e4567-e89b-12d3-a456-426614174000"
+	custom.UUID,
+
+	// type=string
+	custom.Tenant,
+}
+`)
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+
+	r := runtime.New()
+	if err := r.Declare(custom...); err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+
+	got, err := r.Example("uuid", reflect.TypeOf(""))
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+	if want := "123e4567-e89b-12d3-a456-426614174000"; got != want {
+		t.Errorf("Example: got (%v), want (%v)", got, want)
+	}
+
+	// No example is declared.
+	_, err = r.Example("tenant", reflect.TypeOf(""))
+	if want := "cannot find a value satisfying `tenant`"; err == nil || err.Error() != want {
+		t.Errorf("Err: got (%#v), want (%#v)", err, want)
+	}
+}
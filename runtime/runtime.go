@@ -0,0 +1,488 @@
+// Package runtime interprets the schema expressions (e.g. `len(1, 10)`) at
+// runtime, which is an alternative to the generated code. The expressions
+// are bound to the declarations of validators just like code generation, and
+// the validators are created by calling the registered Go functions. The
+// same expressions also drive the synthesis of the values satisfying them
+// (see Registry.Example).
+//
+// Only the schema expressions (i.e. `@schema` and `@returns`) are interpreted,
+// whereas the assertions (i.e. `@assert`), which are arbitrary Go expressions,
+// are not supported.
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/types"
+	"reflect"
+	"sort"
+	"strconv"
+
+	v "github.com/RussellLuo/validating/v3"
+	"github.com/protogodev/validate/decl"
+	"github.com/protogodev/validate/expr"
+	"github.com/protogodev/validate/verr"
+)
+
+// Generic is the runtime form of a generic validator constructor (e.g.
+// `v.Gt[T]`), which is instantiated by typ, i.e. the type of the values to
+// validate. The arguments have been converted to the declared types.
+type Generic func(typ reflect.Type, args []reflect.Value) v.Validator
+
+var (
+	validatorType = reflect.TypeOf((*v.Validator)(nil)).Elem()
+	contextType   = reflect.TypeOf((*context.Context)(nil)).Elem()
+)
+
+// Registry holds the declarations of validators, along with the Go functions
+// creating them.
+type Registry struct {
+	builtin []*decl.Validator
+	custom  []*decl.Validator
+	decls   map[string][]*decl.Validator
+
+	// funcs holds the Go functions, which are either Generic or ordinary
+	// functions returning v.Validator, keyed by the declaration entries.
+	funcs map[string]reflect.Value
+}
+
+// New creates a registry, which holds the builtin validators.
+func New() *Registry {
+	builtin, err := decl.Parse(decl.BuiltinDecls)
+	if err != nil {
+		panic(err)
+	}
+
+	r := &Registry{
+		builtin: builtin,
+		funcs:   make(map[string]reflect.Value),
+	}
+	for entry, fn := range builtinFuncs {
+		r.funcs[entry] = reflect.ValueOf(fn)
+	}
+	if r.decls, _, err = decl.Resolve(r.builtin, nil); err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// Register registers the custom validators declared in src, which is in the
+// same form as the declarations for code generation (i.e. a Go file holding
+// `var _ = []any{...}`). The Go function of each validator is looked up in
+// funcs by its entry (e.g. `customvalidator.UUID`), which must be Generic
+// for a generic validator, or an ordinary function returning v.Validator.
+func (r *Registry) Register(src string, funcs map[string]interface{}) error {
+	custom, err := decl.Parse(src)
+	if err != nil {
+		return err
+	}
+
+	newFuncs := make(map[string]reflect.Value)
+	for _, d := range custom {
+		fn, ok := funcs[d.Entry()]
+		if !ok {
+			return fmt.Errorf("missing function for validator `%s` (%s)", d.Alias, d.Entry())
+		}
+		fv := reflect.ValueOf(fn)
+		if err := checkFunc(d, fv); err != nil {
+			return fmt.Errorf("bad function for validator `%s` (%s): %w", d.Alias, d.Entry(), err)
+		}
+		newFuncs[d.Entry()] = fv
+	}
+
+	return r.register(custom, newFuncs)
+}
+
+// Declare registers the custom validators without their Go functions, e.g.
+// for synthesizing the examples at generation time (see Example), where the
+// declarations have been parsed (see decl.Parse). Since such a validator
+// cannot be evaluated, it is only satisfied by its example (i.e. `example=`
+// in the declaration), if any.
+func (r *Registry) Declare(custom ...*decl.Validator) error {
+	newFuncs := make(map[string]reflect.Value)
+	for _, d := range custom {
+		newFuncs[d.Entry()] = reflect.ValueOf(exampleOnly(d))
+	}
+	return r.register(custom, newFuncs)
+}
+
+// register adds the custom validators, along with their Go functions.
+func (r *Registry) register(custom []*decl.Validator, newFuncs map[string]reflect.Value) error {
+	decls, _, err := decl.Resolve(r.builtin, append(r.custom[:len(r.custom):len(r.custom)], custom...))
+	if err != nil {
+		return err
+	}
+
+	r.custom = append(r.custom, custom...)
+	r.decls = decls
+	for entry, fv := range newFuncs {
+		r.funcs[entry] = fv
+	}
+	return nil
+}
+
+// checkFunc checks whether the function fv matches the declaration d.
+func checkFunc(d *decl.Validator, fv reflect.Value) error {
+	if _, ok := fv.Interface().(Generic); ok {
+		if !d.IsGeneric {
+			return fmt.Errorf("want an ordinary function for the non-generic validator, got runtime.Generic")
+		}
+		return nil
+	}
+	if d.IsGeneric {
+		return fmt.Errorf("want runtime.Generic for the generic validator, got %T", fv.Interface())
+	}
+
+	t := fv.Type()
+	if t.Kind() != reflect.Func || t.NumOut() != 1 || !t.Out(0).Implements(validatorType) {
+		return fmt.Errorf("want a function returning v.Validator, got %s", t)
+	}
+
+	n := t.NumIn()
+	if d.Ctx {
+		if n == 0 || t.In(0) != contextType {
+			return fmt.Errorf("want context.Context as the first argument, got %s", t)
+		}
+		n--
+	}
+	switch {
+	case t.IsVariadic() && d.ArgNum.Min < n-1:
+		return fmt.Errorf("want at least %d arguments, got %s", d.ArgNum.Min, t)
+	case !t.IsVariadic() && (d.ArgNum.Min != n || d.ArgNum.Max != n):
+		return fmt.Errorf("want %d arguments, got %s", d.ArgNum.Max, t)
+	}
+	return nil
+}
+
+// Rule is a compiled schema expression, which validates the values of
+// a specific type.
+type Rule struct {
+	build builder
+}
+
+// builder creates the validator, in which the arguments referencing other
+// variables are resolved by env.
+type builder func(ctx context.Context, env map[string]interface{}) (v.Validator, error)
+
+// Compile compiles the schema expression s (e.g. `len(1, 10)`), which
+// validates the values of type typ. The arguments of the validators may
+// reference the variables in scope (e.g. the other params of a method),
+// whose values will be given when creating the validator (see
+// Rule.Validator).
+func (r *Registry) Compile(s string, typ reflect.Type, scope map[string]reflect.Type) (*Rule, error) {
+	validator, err := r.bind(s, typ, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	build, err := r.compile(validator, typ, scope)
+	if err != nil {
+		return nil, err
+	}
+	return &Rule{build: build}, nil
+}
+
+// bind parses the schema expression s, and binds it to the values of type
+// typ.
+func (r *Registry) bind(s string, typ reflect.Type, scope map[string]reflect.Type) (expr.Validator, error) {
+	if typ == nil {
+		return nil, errors.New("cannot validate untyped nil")
+	}
+
+	validator, err := expr.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+
+	c := newTypeConverter()
+	param := expr.Param{
+		Name:  "value",
+		Type:  c.convert(typ),
+		Scope: make(map[string]types.Type),
+		// A context is always available at runtime.
+		Ctx: "ctx",
+	}
+	for name, t := range scope {
+		if t != nil {
+			param.Scope[name] = c.convert(t)
+		}
+	}
+	if err := validator.Bind(param, r.decls); err != nil {
+		return nil, err
+	}
+	return validator, nil
+}
+
+// Validator creates the validator, in which the arguments referencing other
+// variables are resolved by env, and the context-aware validators are given
+// ctx.
+func (r *Rule) Validator(ctx context.Context, env map[string]interface{}) (v.Validator, error) {
+	return r.build(ctx, env)
+}
+
+// Validate validates value per the schema expression s, which references no
+// other variables. The value must not be untyped nil, whose type is unknown.
+func (r *Registry) Validate(s string, value interface{}) error {
+	rule, err := r.Compile(s, reflect.TypeOf(value), nil)
+	if err != nil {
+		return err
+	}
+	validator, err := rule.Validator(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	if errs := v.Validate(v.Value(value, validator)); errs != nil {
+		return errs
+	}
+	return nil
+}
+
+// ValidateParams validates params (e.g. the params of a method) per rules,
+// both of which are keyed by the param names, just like the generated
+// validation middleware. The rules may reference any params.
+func (r *Registry) ValidateParams(ctx context.Context, rules map[string]string, params map[string]interface{}) error {
+	scope := make(map[string]reflect.Type)
+	for name, value := range params {
+		scope[name] = reflect.TypeOf(value)
+	}
+
+	// Compile the rules in order, which makes the errors deterministic.
+	names := make([]string, 0, len(rules))
+	for name := range rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	schema := make(v.Schema)
+	for _, name := range names {
+		value, ok := params[name]
+		if !ok {
+			return fmt.Errorf("unknown param %q", name)
+		}
+		rule, err := r.Compile(rules[name], reflect.TypeOf(value), scope)
+		if err != nil {
+			return fmt.Errorf("param %q: %w", name, err)
+		}
+		validator, err := rule.Validator(ctx, params)
+		if err != nil {
+			return fmt.Errorf("param %q: %w", name, err)
+		}
+		schema[v.F(name, value)] = validator
+	}
+	if errs := v.Validate(schema); errs != nil {
+		return errs
+	}
+	return nil
+}
+
+func (r *Registry) compile(validator expr.Validator, typ reflect.Type, scope map[string]reflect.Type) (builder, error) {
+	switch x := validator.(type) {
+	case *expr.PointerValidator:
+		return r.compilePointer(x, typ, scope)
+	case *expr.LogicValidator:
+		return r.compileLogic(x, typ, scope)
+	case *expr.ElemValidator:
+		return r.compileElem(x, typ, scope)
+	case *expr.LeafValidator:
+		return r.compileLeaf(x, typ, scope)
+	case *expr.AssertValidator:
+		return nil, fmt.Errorf("cannot interpret assertion %s at runtime (@assert is not supported)", x.Expr)
+	}
+	return nil, fmt.Errorf("unsupported expression %T", validator)
+}
+
+func (r *Registry) compilePointer(x *expr.PointerValidator, typ reflect.Type, scope map[string]reflect.Type) (builder, error) {
+	if typ.Kind() != reflect.Pointer {
+		return r.compile(x.Inner, typ, scope)
+	}
+
+	var nilValidator v.Validator = v.All()
+	if x.Presence == "required" {
+		msg := "is required"
+		if x.Msg != "" {
+			msg = unquote(x.Msg)
+		}
+		nilValidator = verr.Leaf(x.Info(), check(typ, "Nonzero", msg, func(value reflect.Value) bool {
+			return !value.IsNil()
+		}))
+	}
+	if x.Inner == nil {
+		return func(context.Context, map[string]interface{}) (v.Validator, error) {
+			return nilValidator, nil
+		}, nil
+	}
+
+	inner, err := r.compile(x.Inner, typ.Elem(), scope)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, env map[string]interface{}) (v.Validator, error) {
+		validator, err := inner(ctx, env)
+		if err != nil {
+			return nil, err
+		}
+		return v.Func(func(field *v.Field) v.Errors {
+			value, ok := typeAssert(field.Value, typ)
+			if !ok {
+				return v.NewUnsupportedErrors(field, "Nested")
+			}
+			if value.IsNil() {
+				return nilValidator.Validate(field)
+			}
+			return v.Value(value.Elem().Interface(), validator).Validate(field)
+		}), nil
+	}, nil
+}
+
+func (r *Registry) compileLogic(x *expr.LogicValidator, typ reflect.Type, scope map[string]reflect.Type) (builder, error) {
+	left, err := r.compile(x.Left, typ, scope)
+	if err != nil {
+		return nil, err
+	}
+	right := func(context.Context, map[string]interface{}) (v.Validator, error) { return nil, nil }
+	if x.Right != nil {
+		if right, err = r.compile(x.Right, typ, scope); err != nil {
+			return nil, err
+		}
+	}
+
+	return func(ctx context.Context, env map[string]interface{}) (v.Validator, error) {
+		lv, err := left(ctx, env)
+		if err != nil {
+			return nil, err
+		}
+		rv, err := right(ctx, env)
+		if err != nil {
+			return nil, err
+		}
+		switch x.Name {
+		case "!":
+			return v.Not(lv), nil
+		case "&&":
+			return v.All(lv, rv), nil
+		default: // "||"
+			return v.Any(lv, rv), nil
+		}
+	}, nil
+}
+
+func (r *Registry) compileElem(x *expr.ElemValidator, typ reflect.Type, scope map[string]reflect.Type) (builder, error) {
+	elemType := typ.Elem()
+	if x.Name == "keys" {
+		elemType = typ.Key()
+	}
+	inner, err := r.compile(x.Inner, elemType, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	// The name of the composite validator in the generated code.
+	name := "Map"
+	switch typ.Kind() {
+	case reflect.Slice:
+		name = "Slice"
+	case reflect.Array:
+		name = "Nested"
+	}
+
+	return func(ctx context.Context, env map[string]interface{}) (v.Validator, error) {
+		validator, err := inner(ctx, env)
+		if err != nil {
+			return nil, err
+		}
+		return v.Func(func(field *v.Field) (errs v.Errors) {
+			value, ok := typeAssert(field.Value, typ)
+			if !ok {
+				return v.NewUnsupportedErrors(field, name)
+			}
+
+			if typ.Kind() != reflect.Map {
+				for i := 0; i < value.Len(); i++ {
+					name := field.Name + fmt.Sprintf("[%d]", i)
+					errs.Append(validator.Validate(v.F(name, value.Index(i).Interface()))...)
+				}
+				return errs
+			}
+
+			iter := value.MapRange()
+			for iter.Next() {
+				name := field.Name + fmt.Sprintf("[%v]", iter.Key().Interface())
+				elem := iter.Value()
+				if x.Name == "keys" {
+					elem = iter.Key()
+				}
+				errs.Append(validator.Validate(v.F(name, elem.Interface()))...)
+			}
+			return errs
+		}), nil
+	}, nil
+}
+
+func (r *Registry) compileLeaf(x *expr.LeafValidator, typ reflect.Type, scope map[string]reflect.Type) (builder, error) {
+	// Special case for validator `_`.
+	if x.Name == "_" {
+		return func(context.Context, map[string]interface{}) (v.Validator, error) {
+			return v.Func(func(field *v.Field) v.Errors {
+				s, ok := field.Value.(interface{ Schema() v.Schema })
+				if !ok {
+					return v.NewUnsupportedErrors(field, "Schema")
+				}
+				return s.Schema().Validate(field)
+			}), nil
+		}, nil
+	}
+
+	d := x.MatchedDecl()
+	fn, ok := r.funcs[d.Entry()]
+	if !ok {
+		return nil, fmt.Errorf("missing function for validator `%s` (%s)", d.Alias, d.Entry())
+	}
+
+	args := make([]arg, len(x.Args))
+	for i, s := range x.Args {
+		want := argType(d, fn, typ, i)
+		a, err := evalArg(s, want, scope)
+		if err != nil {
+			return nil, fmt.Errorf("argument %s of validator `%s`: %w", s, x.Name, err)
+		}
+		args[i] = a
+	}
+
+	msg := unquote(x.Message())
+	info := x.Info()
+
+	return func(ctx context.Context, env map[string]interface{}) (v.Validator, error) {
+		var in []reflect.Value
+		if d.Ctx {
+			in = append(in, reflect.ValueOf(&ctx).Elem())
+		}
+		for _, a := range args {
+			value, err := a.resolve(env)
+			if err != nil {
+				return nil, fmt.Errorf("argument %s of validator `%s`: %w", a.ref, x.Name, err)
+			}
+			in = append(in, value)
+		}
+
+		var validator v.Validator
+		if g, ok := fn.Interface().(Generic); ok {
+			validator = g(typ, in)
+		} else {
+			validator = fn.Call(in)[0].Interface().(v.Validator)
+		}
+		if mv, ok := validator.(*v.MessageValidator); ok && msg != "" {
+			mv.Msg(msg)
+		}
+		return verr.Leaf(info, validator), nil
+	}, nil
+}
+
+// unquote returns the value of the string literal s, or s itself if it is
+// not a string literal (e.g. an empty string).
+func unquote(s string) string {
+	if value, err := strconv.Unquote(s); err == nil {
+		return value
+	}
+	return s
+}
@@ -0,0 +1,20 @@
+package runtime
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/protogodev/validate/expr"
+)
+
+func TestRegistry_compile_Assert(t *testing.T) {
+	// The assertions are never produced by Compile, but must be rejected
+	// explicitly if given.
+	r := New()
+	_, err := r.compile(&expr.AssertValidator{Expr: "min < max"}, reflect.TypeOf(0), nil)
+
+	wantErr := "cannot interpret assertion min < max at runtime (@assert is not supported)"
+	if err == nil || err.Error() != wantErr {
+		t.Errorf("Err: got (%#v), want (%#v)", err, wantErr)
+	}
+}
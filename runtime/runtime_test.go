@@ -0,0 +1,390 @@
+package runtime_test
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	v "github.com/RussellLuo/validating/v3"
+	"github.com/google/go-cmp/cmp"
+	"github.com/protogodev/validate/runtime"
+	"github.com/protogodev/validate/verr"
+)
+
+type Level int
+
+type Address struct {
+	Zip string
+}
+
+func (a Address) Schema() v.Schema {
+	return v.Schema{
+		v.F("zip", a.Zip): v.LenString(5, 5),
+	}
+}
+
+func TestRegistry_Validate(t *testing.T) {
+	name := "bob"
+	tests := []struct {
+		name    string
+		inStr   string
+		inValue interface{}
+		wantErr string
+	}{
+		{
+			name:    "len string",
+			inStr:   `len(0, 2).msg("bad length")`,
+			inValue: "abc",
+			wantErr: "INVALID(bad length)",
+		},
+		{
+			name:    "len default",
+			inStr:   `len(min: 1)`,
+			inValue: "abc",
+		},
+		{
+			name:    "match",
+			inStr:   "match(`^\\w+$`)",
+			inValue: "a b",
+			wantErr: "INVALID(does not match the given regular expression)",
+		},
+		{
+			name:    "gt named",
+			inStr:   `gt(1) && lt(1 << 4)`,
+			inValue: Level(1),
+			wantErr: "INVALID(is lower than or equal to the given value)",
+		},
+		{
+			name:    "range float",
+			inStr:   `xrange(0, 1.5)`,
+			inValue: 1.5,
+		},
+		{
+			name:    "in",
+			inStr:   `in("a", "b")`,
+			inValue: "c",
+			wantErr: "INVALID(is not one of the given values)",
+		},
+		{
+			name:    "not",
+			inStr:   `!eq(0)`,
+			inValue: uint8(0),
+			wantErr: "INVALID(is invalid)",
+		},
+		{
+			name:    "any",
+			inStr:   `eq(1) || eq(2)`,
+			inValue: 2,
+		},
+		{
+			name:    "each",
+			inStr:   `len(max: 2) && each(nonzero)`,
+			inValue: []string{"a", ""},
+			wantErr: "[1]: INVALID(is zero valued)",
+		},
+		{
+			name:    "each array",
+			inStr:   `each(gte(0))`,
+			inValue: [2]int{0, -1},
+			wantErr: "[1]: INVALID(is lower than the given value)",
+		},
+		{
+			name:    "values",
+			inStr:   `keys(len(1, 2)) && values(gt(0))`,
+			inValue: map[string]int{"a": 0},
+			wantErr: "[a]: INVALID(is lower than or equal to the given value)",
+		},
+		{
+			name:    "required",
+			inStr:   `required.code("E_NAME") && len(1, 2)`,
+			inValue: (*string)(nil),
+			wantErr: "INVALID(is required)",
+		},
+		{
+			name:    "pointer",
+			inStr:   `len(1, 2)`,
+			inValue: &name,
+			wantErr: "INVALID(has an invalid length)",
+		},
+		{
+			name:    "optional",
+			inStr:   `optional`,
+			inValue: (*string)(nil),
+		},
+		{
+			name:    "nested",
+			inStr:   `_`,
+			inValue: Address{Zip: "123"},
+			wantErr: "zip: INVALID(has an invalid length)",
+		},
+		{
+			name:    "time",
+			inStr:   `nonzero && after("2024-01-01") && before("2030-01-01T08:00:00+08:00")`,
+			inValue: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			wantErr: "INVALID(is not after the given time)",
+		},
+		{
+			name:    "duration",
+			inStr:   `xrange("1s", "1h30m")`,
+			inValue: 2 * time.Hour,
+			wantErr: "INVALID(is not between the given range)",
+		},
+		{
+			name:    "bad type",
+			inStr:   `len(1, 2)`,
+			inValue: 1,
+			wantErr: "cannot use validator `len` on type *types.Basic",
+		},
+		{
+			name:    "bad argument",
+			inStr:   `gt("a")`,
+			inValue: 1,
+			wantErr: `1:4 cannot use "a" (type untyped string) as int in argument to validator ` + "`gt`",
+		},
+		{
+			name:    "unknown constant",
+			inStr:   `gt(limits.Min)`,
+			inValue: 1,
+			wantErr: "argument limits.Min of validator `gt`: cannot be evaluated at runtime (want a literal, a constant expression or a variable in scope)",
+		},
+		{
+			name:    "untyped nil",
+			inStr:   `required`,
+			inValue: nil,
+			wantErr: "cannot validate untyped nil",
+		},
+	}
+
+	r := runtime.New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotErr string
+			if err := r.Validate(tt.inStr, tt.inValue); err != nil {
+				gotErr = err.Error()
+			}
+			if gotErr != tt.wantErr {
+				t.Errorf("Err: got (%#v), want (%#v)", gotErr, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegistry_ValidateParams(t *testing.T) {
+	rules := map[string]string{
+		"name": `required.code("E_NAME") && len(1, 10)`,
+		"min":  `gte(0)`,
+		"max":  `gt(min)`,
+	}
+
+	tests := []struct {
+		name        string
+		inParams    map[string]interface{}
+		wantDetails []verr.Detail
+		wantErr     string
+	}{
+		{
+			name: "ok",
+			inParams: map[string]interface{}{
+				"name": &[]string{"bob"}[0],
+				"min":  1,
+				"max":  2,
+			},
+		},
+		{
+			name: "untyped nil",
+			inParams: map[string]interface{}{
+				"name": nil,
+				"min":  1,
+				"max":  2,
+			},
+			wantErr: `param "name": cannot validate untyped nil`,
+		},
+		{
+			name: "invalid",
+			inParams: map[string]interface{}{
+				"name": (*string)(nil),
+				"min":  1,
+				"max":  1,
+			},
+			wantDetails: []verr.Detail{
+				{Field: "max", Kind: "INVALID", Validator: "gt", Args: []string{"min"}, Message: "must be greater than min"},
+				{Field: "name", Kind: "INVALID", Validator: "required", Message: "is required", Code: "E_NAME"},
+			},
+		},
+	}
+
+	r := runtime.New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := r.ValidateParams(context.Background(), rules, tt.inParams)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("Err: got (%#v), want (%#v)", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil && verr.Details(err) == nil {
+				t.Fatalf("err: %v\n", err)
+			}
+
+			gotDetails := verr.Details(err)
+			// Sort by field, since the order of the errors is undetermined.
+			sort.Slice(gotDetails, func(i, j int) bool {
+				return gotDetails[i].Field < gotDetails[j].Field
+			})
+			if !cmp.Equal(gotDetails, tt.wantDetails) {
+				diff := cmp.Diff(gotDetails, tt.wantDetails)
+				t.Errorf("Want - Got: %s", diff)
+			}
+		})
+	}
+}
+
+type tenantKey struct{}
+
+func Prefix(ctx context.Context, prefix string) v.Validator {
+	return v.Is(func(s string) bool {
+		return strings.HasPrefix(s, ctx.Value(tenantKey{}).(string)+prefix)
+	})
+}
+
+func Between(typ reflect.Type, args []reflect.Value) v.Validator {
+	return v.Is(func(x int) bool {
+		return args[0].Int() <= int64(x) && int64(x) <= args[1].Int()
+	})
+}
+
+const customDecls = `package custom
+
+import "example.com/custom"
+
+var _ = []any{
+	// type=string args=prefix:string ctx=true
+	custom.Prefix,
+
+	// type=integer args=min:T,max:T
+	custom.Between[int],
+
+	// type=string args=0 override=true
+	custom.Nonzero,
+}
+`
+
+func TestRegistry_Register(t *testing.T) {
+	r := runtime.New()
+	err := r.Register(customDecls, map[string]interface{}{
+		"custom.Prefix":  Prefix,
+		"custom.Between": runtime.Generic(Between),
+		"custom.Nonzero": func() v.Validator { return v.Is(func(s string) bool { return strings.TrimSpace(s) != "" }) },
+	})
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme-")
+	tests := []struct {
+		name    string
+		inStr   string
+		inValue interface{}
+		wantErr string
+	}{
+		{
+			name:    "ctx",
+			inStr:   `prefix("u")`,
+			inValue: "acme-u1",
+		},
+		{
+			name:    "ctx invalid",
+			inStr:   `prefix("u")`,
+			inValue: "u1",
+			wantErr: "INVALID(is invalid)",
+		},
+		{
+			name:    "generic",
+			inStr:   `between(max: 10, min: 1)`,
+			inValue: 11,
+			wantErr: "INVALID(is invalid)",
+		},
+		{
+			name:    "override",
+			inStr:   `nonzero`,
+			inValue: " ",
+			wantErr: "INVALID(is invalid)",
+		},
+		{
+			name:    "builtin",
+			inStr:   `nonzero`,
+			inValue: 0,
+			wantErr: "INVALID(is zero valued)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := r.Compile(tt.inStr, reflect.TypeOf(tt.inValue), nil)
+			if err != nil {
+				t.Fatalf("err: %v\n", err)
+			}
+			validator, err := rule.Validator(ctx, nil)
+			if err != nil {
+				t.Fatalf("err: %v\n", err)
+			}
+
+			var gotErr string
+			if err := v.Validate(v.Value(tt.inValue, validator)); err != nil {
+				gotErr = err.Error()
+			}
+			if gotErr != tt.wantErr {
+				t.Errorf("Err: got (%#v), want (%#v)", gotErr, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegistry_Register_BadFunc(t *testing.T) {
+	tests := []struct {
+		name    string
+		inFuncs map[string]interface{}
+		wantErr string
+	}{
+		{
+			name:    "missing",
+			inFuncs: map[string]interface{}{},
+			wantErr: "missing function for validator `uuid` (custom.UUID)",
+		},
+		{
+			name:    "bad arguments",
+			inFuncs: map[string]interface{}{"custom.UUID": func(s string) v.Validator { return nil }},
+			wantErr: "bad function for validator `uuid` (custom.UUID): want 0 arguments, got func(string) validating.Validator",
+		},
+		{
+			name:    "generic",
+			inFuncs: map[string]interface{}{"custom.UUID": runtime.Generic(Between)},
+			wantErr: "bad function for validator `uuid` (custom.UUID): want an ordinary function for the non-generic validator, got runtime.Generic",
+		},
+	}
+
+	src := `package custom
+
+import "example.com/custom"
+
+var _ = []any{
+	// type=string args=0
+	custom.UUID,
+}
+`
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotErr string
+			if err := runtime.New().Register(src, tt.inFuncs); err != nil {
+				gotErr = err.Error()
+			}
+			if gotErr != tt.wantErr {
+				t.Errorf("Err: got (%#v), want (%#v)", gotErr, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package runtime
+
+import (
+	"go/token"
+	"go/types"
+	"reflect"
+	"time"
+)
+
+// basicTypes maps the reflect kinds to the basic types.
+var basicTypes = map[reflect.Kind]types.BasicKind{
+	reflect.Bool:          types.Bool,
+	reflect.Int:           types.Int,
+	reflect.Int8:          types.Int8,
+	reflect.Int16:         types.Int16,
+	reflect.Int32:         types.Int32,
+	reflect.Int64:         types.Int64,
+	reflect.Uint:          types.Uint,
+	reflect.Uint8:         types.Uint8,
+	reflect.Uint16:        types.Uint16,
+	reflect.Uint32:        types.Uint32,
+	reflect.Uint64:        types.Uint64,
+	reflect.Uintptr:       types.Uintptr,
+	reflect.Float32:       types.Float32,
+	reflect.Float64:       types.Float64,
+	reflect.Complex64:     types.Complex64,
+	reflect.Complex128:    types.Complex128,
+	reflect.String:        types.String,
+	reflect.UnsafePointer: types.UnsafePointer,
+}
+
+// typeConverter converts the reflect types to the equivalent go/types ones,
+// which are used to bind the expressions to the declarations.
+type typeConverter struct {
+	pkgs  map[string]*types.Package
+	named map[reflect.Type]*types.Named
+}
+
+func newTypeConverter() *typeConverter {
+	c := &typeConverter{
+		pkgs:  make(map[string]*types.Package),
+		named: make(map[reflect.Type]*types.Named),
+	}
+	// The time types are always declared, since the arguments of kind time
+	// or duration are looked up from the package time.
+	c.convert(reflect.TypeOf(time.Time{}))
+	c.convert(reflect.TypeOf(time.Duration(0)))
+	return c
+}
+
+func (c *typeConverter) pkg(path string) *types.Package {
+	if path == "" {
+		return nil
+	}
+	pkg, ok := c.pkgs[path]
+	if !ok {
+		pkg = types.NewPackage(path, pkgName(path))
+		c.pkgs[path] = pkg
+	}
+	return pkg
+}
+
+// pkgName guesses the package name from the import path.
+func pkgName(path string) string {
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i] == '/' {
+			return path[i+1:]
+		}
+	}
+	return path
+}
+
+func (c *typeConverter) convert(t reflect.Type) types.Type {
+	if t.Name() != "" {
+		if t.PkgPath() == "" {
+			// The predeclared types (e.g. `int` or `error`).
+			return types.Universe.Lookup(t.Name()).Type()
+		}
+		return c.convertNamed(t)
+	}
+	return c.convertUnnamed(t)
+}
+
+func (c *typeConverter) convertNamed(t reflect.Type) types.Type {
+	if named, ok := c.named[t]; ok {
+		return named
+	}
+
+	pkg := c.pkg(t.PkgPath())
+	obj := types.NewTypeName(token.NoPos, pkg, t.Name(), nil)
+	named := types.NewNamed(obj, nil, nil)
+	pkg.Scope().Insert(obj)
+	// Register in advance for the recursive types.
+	c.named[t] = named
+
+	named.SetUnderlying(c.convertUnnamed(t))
+	return named
+}
+
+// convertUnnamed converts the underlying type of t.
+func (c *typeConverter) convertUnnamed(t reflect.Type) types.Type {
+	if kind, ok := basicTypes[t.Kind()]; ok {
+		return types.Typ[kind]
+	}
+
+	switch t.Kind() {
+	case reflect.Array:
+		return types.NewArray(c.convert(t.Elem()), int64(t.Len()))
+	case reflect.Slice:
+		return types.NewSlice(c.convert(t.Elem()))
+	case reflect.Map:
+		return types.NewMap(c.convert(t.Key()), c.convert(t.Elem()))
+	case reflect.Pointer:
+		return types.NewPointer(c.convert(t.Elem()))
+	case reflect.Chan:
+		dir := types.SendRecv
+		switch t.ChanDir() {
+		case reflect.SendDir:
+			dir = types.SendOnly
+		case reflect.RecvDir:
+			dir = types.RecvOnly
+		}
+		return types.NewChan(dir, c.convert(t.Elem()))
+	case reflect.Func:
+		return c.convertFunc(t)
+	case reflect.Interface:
+		var methods []*types.Func
+		for i := 0; i < t.NumMethod(); i++ {
+			m := t.Method(i)
+			sig := c.convertFunc(m.Type)
+			methods = append(methods, types.NewFunc(token.NoPos, c.pkg(m.PkgPath), m.Name, sig))
+		}
+		return types.NewInterfaceType(methods, nil).Complete()
+	case reflect.Struct:
+		var fields []*types.Var
+		var tags []string
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			fields = append(fields, types.NewField(token.NoPos, c.pkg(f.PkgPath), f.Name, c.convert(f.Type), f.Anonymous))
+			tags = append(tags, string(f.Tag))
+		}
+		return types.NewStruct(fields, tags)
+	}
+
+	panic("unreachable")
+}
+
+func (c *typeConverter) convertFunc(t reflect.Type) *types.Signature {
+	var params, results []*types.Var
+	for i := 0; i < t.NumIn(); i++ {
+		params = append(params, types.NewParam(token.NoPos, nil, "", c.convert(t.In(i))))
+	}
+	for i := 0; i < t.NumOut(); i++ {
+		results = append(results, types.NewParam(token.NoPos, nil, "", c.convert(t.Out(i))))
+	}
+	return types.NewSignatureType(nil, nil, nil, types.NewTuple(params...), types.NewTuple(results...), t.IsVariadic())
+}
@@ -0,0 +1,331 @@
+package validate
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+
+	"github.com/protogodev/protogo/parser/ifacetool"
+	"github.com/protogodev/validate/decl"
+	"github.com/protogodev/validate/expr"
+)
+
+// methodSchema holds the fields to be validated for a method.
+type methodSchema struct {
+	Params  []*schemaField
+	Returns []*schemaField
+}
+
+// schemaField is a field in the generated schema.
+type schemaField struct {
+	// Name is the field path, e.g. `user.Name`.
+	Name string
+	// Value is the Go expression of the field value, e.g. `user.Name`.
+	Value string
+	// Validator is the validating-style expression string.
+	Validator string
+	// NilChecks are the conditions that must be satisfied before accessing
+	// the field value, e.g. `req.Address != nil`.
+	NilChecks []string
+}
+
+type schemaBuilder struct {
+	decls     map[string][]*decl.Validator
+	qualifier types.Qualifier
+
+	// scope holds the variables, which can be referenced in expressions.
+	scope map[string]types.Type
+	// ctx is the name of the context.Context variable, if any.
+	ctx string
+
+	// imports maps the qualifiers to the import paths of the packages, which
+	// can be referenced by the assertions (see expr.Param).
+	imports  map[string]string
+	importer types.Importer
+}
+
+// WithScope returns a copy of b, whose expressions can reference vars.
+func (b *schemaBuilder) WithScope(vars []*ifacetool.Param) *schemaBuilder {
+	scope := make(map[string]types.Type)
+	for _, p := range vars {
+		scope[p.Name] = p.Type
+	}
+
+	newB := *b
+	newB.scope = scope
+	return &newB
+}
+
+// WithContext returns a copy of b, whose context-aware validators will
+// receive the context.Context variable ctx.
+func (b *schemaBuilder) WithContext(ctx string) *schemaBuilder {
+	newB := *b
+	newB.ctx = ctx
+	return &newB
+}
+
+// WithImports returns a copy of b, whose assertions can reference the
+// packages imported by the source file in dir. If dir is empty, only the
+// packages reachable from the types in scope can be referenced.
+func (b *schemaBuilder) WithImports(imports []ifacetool.Import, dir string) *schemaBuilder {
+	qualified := make(map[string]string)
+	for _, i := range imports {
+		name := i.Alias
+		if name == "" {
+			name = importName(i.Path)
+		}
+		qualified[name] = i.Path
+	}
+
+	newB := *b
+	newB.imports = qualified
+	if dir != "" {
+		newB.importer = dirImporter{from: sourceImporter(), dir: dir}
+	}
+	return &newB
+}
+
+// Build builds the schema fields for the given variables (i.e. params or
+// results), per the options from the corresponding annotation. The option
+// values will be parsed by parse (e.g. expr.Parse).
+func (b *schemaBuilder) Build(vars []*ifacetool.Param, options []Option, parse parseFunc) ([]*schemaField, error) {
+	fields, errs := b.build(vars, options, parse)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return fields, nil
+}
+
+// build is like Build, but collects the errors of all the options.
+func (b *schemaBuilder) build(vars []*ifacetool.Param, options []Option, parse parseFunc) ([]*schemaField, schemaErrors) {
+	var fields []*schemaField
+	var errs schemaErrors
+	matched := make([]bool, len(options))
+	for _, p := range vars {
+		for i, opt := range options {
+			path := strings.Split(opt.K, ".")
+			if path[0] != p.Name {
+				continue
+			}
+			matched[i] = true
+
+			f, err := b.buildField(p, path[1:], opt.V, parse)
+			if err != nil {
+				errs = append(errs, &schemaError{Opt: opt, Err: err})
+				continue
+			}
+			fields = append(fields, f)
+		}
+	}
+
+	for i, opt := range options {
+		if !matched[i] {
+			errs = append(errs, b.unmatchedError(vars, opt))
+		}
+	}
+	return fields, errs
+}
+
+// unmatchedError returns the error for the option, whose key matches none
+// of the given variables.
+func (b *schemaBuilder) unmatchedError(vars []*ifacetool.Param, opt Option) *schemaError {
+	name := strings.Split(opt.K, ".")[0]
+	if b.ctx != "" && name == b.ctx {
+		return &schemaError{
+			Opt: opt,
+			Err: fmt.Errorf("cannot validate the context.Context param %s", name),
+		}
+	}
+
+	var names []string
+	for _, p := range vars {
+		names = append(names, p.Name)
+	}
+
+	err := fmt.Errorf("unknown name %q", name)
+	if suggestion := suggest(name, names); suggestion != "" {
+		err = fmt.Errorf("unknown name %q (did you mean %q?)", name, suggestion)
+	}
+	return &schemaError{Opt: opt, Err: err, Unknown: true}
+}
+
+// schemaError is an error of an annotation option.
+type schemaError struct {
+	Method string // The method name, if any.
+	Anno   string // The annotation name (e.g. `schema`), if any.
+	Opt    Option // The option, if any.
+	Err    error
+
+	// Unknown reports whether the option key matches no variable.
+	Unknown bool
+}
+
+func (e *schemaError) Error() string {
+	var prefix string
+	if e.Method != "" {
+		prefix += "method " + e.Method + ": "
+	}
+	if e.Anno != "" {
+		prefix += "@" + e.Anno + ": "
+	}
+	if e.Opt.K != "" {
+		prefix += e.Opt.K + ": "
+	}
+	return prefix + e.Err.Error()
+}
+
+// schemaErrors is a list of schema errors.
+type schemaErrors []*schemaError
+
+func (e schemaErrors) Error() string {
+	strs := make([]string, len(e))
+	for i, err := range e {
+		strs[i] = err.Error()
+	}
+	return strings.Join(strs, "\n")
+}
+
+// split splits e into the errors of unknown keys and the other errors.
+func (e schemaErrors) split() (unknown, others schemaErrors) {
+	for _, err := range e {
+		if err.Unknown {
+			unknown = append(unknown, err)
+		} else {
+			others = append(others, err)
+		}
+	}
+	return
+}
+
+func (b *schemaBuilder) buildField(p *ifacetool.Param, path []string, schema string, parse parseFunc) (*schemaField, error) {
+	f := &schemaField{
+		Name:  p.Name,
+		Value: p.Name,
+	}
+
+	typ := p.Type
+	for _, name := range path {
+		if ptr, ok := typ.Underlying().(*types.Pointer); ok {
+			f.NilChecks = append(f.NilChecks, f.Value+" != nil")
+			typ = ptr.Elem()
+		}
+
+		field, err := b.lookupField(typ, name)
+		if err != nil {
+			return nil, err
+		}
+
+		f.Name += "." + name
+		f.Value += "." + name
+		typ = field.Type()
+	}
+
+	validator, err := b.bindExpr(f.Value, typ, schema, parse)
+	if err != nil {
+		return nil, err
+	}
+
+	f.Validator = validator
+	return f, nil
+}
+
+type parseFunc func(string) (expr.Validator, error)
+
+// bindExpr parses the schema expression, binds it to the value of the given
+// type and returns the validating-style expression string.
+func (b *schemaBuilder) bindExpr(value string, typ types.Type, schema string, parse parseFunc) (string, error) {
+	validator, err := b.bind(value, typ, schema, parse)
+	if err != nil {
+		return "", err
+	}
+	return validator.ExprString(), nil
+}
+
+// bind parses the schema expression and binds it to the value of the given type.
+func (b *schemaBuilder) bind(value string, typ types.Type, schema string, parse parseFunc) (expr.Validator, error) {
+	validator, err := parse(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	param := expr.Param{
+		Name:      value,
+		Type:      typ,
+		Qualifier: b.qualifier,
+		Scope:     b.scope,
+		Ctx:       b.ctx,
+		Imports:   b.imports,
+		Importer:  b.importer,
+	}
+	if err := validator.Bind(param, b.decls); err != nil {
+		return nil, err
+	}
+
+	return validator, nil
+}
+
+// lookupField finds the field, which is accessible from the generated code,
+// by the given name in the struct typ.
+func (b *schemaBuilder) lookupField(typ types.Type, name string) (*types.Var, error) {
+	typeString := types.TypeString(typ, b.qualifier)
+
+	s, ok := typ.Underlying().(*types.Struct)
+	if !ok {
+		return nil, fmt.Errorf("type %s is not a struct", typeString)
+	}
+
+	for i := 0; i < s.NumFields(); i++ {
+		field := s.Field(i)
+		if field.Name() != name {
+			continue
+		}
+
+		// Unexported fields are only accessible within the same package.
+		if !field.Exported() && field.Pkg() != nil && b.qualifier(field.Pkg()) != "" {
+			return nil, fmt.Errorf("field %s of type %s is unexported", name, typeString)
+		}
+		return field, nil
+	}
+
+	return nil, fmt.Errorf("type %s has no field %s", typeString, name)
+}
+
+// suggest returns the candidate most similar to name, or an empty string if
+// none of the candidates is similar enough.
+func suggest(name string, candidates []string) string {
+	best, bestDist := "", len(name)/2+1
+	for _, c := range candidates {
+		if d := editDistance(strings.ToLower(name), strings.ToLower(c)); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, minInt(curr[j-1]+1, prev[j-1]+cost))
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
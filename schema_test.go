@@ -0,0 +1,125 @@
+package validate
+
+import (
+	"go/types"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/protogodev/protogo/parser/ifacetool"
+	"github.com/protogodev/validate/decl"
+	"github.com/protogodev/validate/expr"
+)
+
+func TestSchemaBuilder_Build(t *testing.T) {
+	pkg := types.NewPackage("example.com/other", "other")
+	address := types.NewNamed(types.NewTypeName(0, pkg, "Address", nil), types.NewStruct([]*types.Var{
+		types.NewField(0, pkg, "Zip", types.Typ[types.String], false),
+		types.NewField(0, pkg, "secret", types.Typ[types.String], false),
+	}, nil), nil)
+	user := types.NewNamed(types.NewTypeName(0, pkg, "User", nil), types.NewStruct([]*types.Var{
+		types.NewField(0, pkg, "Name", types.Typ[types.String], false),
+		types.NewField(0, pkg, "Address", types.NewPointer(address), false),
+	}, nil), nil)
+
+	params := []*ifacetool.Param{
+		{Name: "user", Type: types.NewPointer(user)},
+		{Name: "age", Type: types.Typ[types.Int]},
+	}
+
+	tests := []struct {
+		name       string
+		in         []Option
+		want       []*schemaField
+		wantErrStr string
+	}{
+		{
+			name: "param",
+			in:   []Option{{K: "age", V: "gte(0)"}},
+			want: []*schemaField{
+				{Name: "age", Value: "age", Validator: `verr.Leaf(verr.Info{Validator: "gte", Args: []string{"0"}}, v.Gte[int](0))`},
+			},
+		},
+		{
+			name: "field path",
+			in: []Option{
+				{K: "user.Name", V: "len(1, 10)"},
+				{K: "user.Address.Zip", V: "len(5, 5)"},
+			},
+			want: []*schemaField{
+				{
+					Name:      "user.Name",
+					Value:     "user.Name",
+					Validator: `verr.Leaf(verr.Info{Validator: "len", Args: []string{"1", "10"}}, v.LenString(1, 10))`,
+					NilChecks: []string{"user != nil"},
+				},
+				{
+					Name:      "user.Address.Zip",
+					Value:     "user.Address.Zip",
+					Validator: `verr.Leaf(verr.Info{Validator: "len", Args: []string{"5", "5"}}, v.LenString(5, 5))`,
+					NilChecks: []string{"user != nil", "user.Address != nil"},
+				},
+			},
+		},
+		{
+			name:       "unknown field",
+			in:         []Option{{K: "user.Nme", V: "len(1, 10)"}},
+			wantErrStr: "user.Nme: type other.User has no field Nme",
+		},
+		{
+			name:       "unexported field",
+			in:         []Option{{K: "user.Address.secret", V: "len(1, 10)"}},
+			wantErrStr: "user.Address.secret: field secret of type other.Address is unexported",
+		},
+		{
+			name:       "unknown param",
+			in:         []Option{{K: "usr.Name", V: "len(1, 10)"}},
+			wantErrStr: `usr.Name: unknown name "usr" (did you mean "user"?)`,
+		},
+		{
+			name:       "unknown param without suggestion",
+			in:         []Option{{K: "address", V: "nonzero"}},
+			wantErrStr: `address: unknown name "address"`,
+		},
+		{
+			name:       "non-struct",
+			in:         []Option{{K: "age.Value", V: "gte(0)"}},
+			wantErrStr: "age.Value: type int is not a struct",
+		},
+	}
+
+	builtin, err := decl.Parse(decl.BuiltinDecls)
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+	decls := make(map[string][]*decl.Validator)
+	for _, d := range builtin {
+		decls[d.Alias] = append(decls[d.Alias], d)
+	}
+
+	b := &schemaBuilder{
+		decls: decls,
+		qualifier: func(pkg *types.Package) string {
+			return pkg.Name()
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := b.Build(params, tt.in, expr.Parse)
+			if err != nil {
+				if err.Error() != tt.wantErrStr {
+					t.Fatalf("Err: got (%#v), want (%#v)", err.Error(), tt.wantErrStr)
+				}
+				return
+			}
+			if tt.wantErrStr != "" {
+				t.Fatalf("Err: got (%#v), want (%#v)", "", tt.wantErrStr)
+			}
+
+			if !cmp.Equal(got, tt.want) {
+				diff := cmp.Diff(got, tt.want)
+				t.Errorf("Want - Got: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,370 @@
+package validate
+
+import (
+	_ "embed"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	protogocmd "github.com/protogodev/protogo/cmd"
+	"github.com/protogodev/protogo/generator"
+	"github.com/protogodev/protogo/parser/ifacetool"
+	"github.com/protogodev/validate/expr"
+	"golang.org/x/tools/go/packages"
+)
+
+//go:embed struct_template.go.tmpl
+var structTemplate string
+
+var reFieldSchema = regexp.MustCompile(`^\s*@schema:\s*(.+)$`)
+
+func init() {
+	protogocmd.MustRegister(&protogocmd.Plugin{
+		Name: "validate-struct",
+		Help: "Generate Schema() methods for structs",
+		Cmd:  &StructGenerator{},
+	})
+}
+
+// StructGenerator generates the `Schema()` method for structs, per the
+// `@schema` annotation (or the `validate` tag) of each field.
+type StructGenerator struct {
+	Formatted bool     `name:"fmt" default:"true" help:"whether to make the generated code formatted"`
+	Custom    []string `name:"custom" help:"the declarations of custom validators, which can be files, globs, directories or Go import paths (repeatable)"`
+
+	SrcFilename string   `arg:"" name:"source-file" help:"source file"`
+	StructNames []string `arg:"" optional:"" name:"struct-name" help:"struct names (defaults to all annotated structs in the source file)"`
+}
+
+func (g *StructGenerator) Run() error {
+	srcFilename, err := filepath.Abs(g.SrcFilename)
+	if err != nil {
+		return err
+	}
+
+	pkg, file, err := loadFile(srcFilename)
+	if err != nil {
+		return err
+	}
+
+	f, err := g.Generate(pkg, file, filepath.Join(filepath.Dir(srcFilename), structGenFilename(srcFilename)))
+	if err != nil {
+		return err
+	}
+
+	return f.Write()
+}
+
+// Generate generates the `Schema()` methods for the structs declared in file,
+// which belongs to pkg.
+func (g *StructGenerator) Generate(pkg *types.Package, file *ast.File, targetFileName string) (*generator.File, error) {
+	completeDecls, err := buildCompleteDecls(g.Custom, fileImports(file))
+	if err != nil {
+		return nil, err
+	}
+	printWarnings(shadowWarnings(completeDecls))
+	imports := mergeImports(completeDecls.imports, fileImports(file))
+
+	builder := &schemaBuilder{
+		decls: completeDecls.validators,
+		qualifier: func(p *types.Package) string {
+			if p.Path() == pkg.Path() {
+				return ""
+			}
+			for _, i := range file.Imports {
+				if strings.Trim(i.Path.Value, `"`) == p.Path() && i.Name != nil {
+					return i.Name.Name
+				}
+			}
+			return p.Name()
+		},
+	}
+
+	structs, err := buildStructSchemas(builder, pkg, file, g.StructNames, imports)
+	if err != nil {
+		return nil, err
+	}
+
+	tmplData := struct {
+		PkgName string
+		Imports []ifacetool.Import
+		Structs []*structSchema
+	}{
+		PkgName: pkg.Name(),
+		Imports: imports,
+		Structs: structs,
+	}
+
+	return generator.Generate(structTemplate, tmplData, generator.Options{
+		Formatted:      g.Formatted,
+		TargetFileName: targetFileName,
+	})
+}
+
+// structSchema holds the fields to be validated for a struct.
+type structSchema struct {
+	Name     string
+	Receiver string
+	Fields   []*schemaField
+}
+
+// buildStructSchemas builds the schemas for the given structs declared in
+// file. If no name is given, all the structs with annotated fields will
+// be included. The receivers of the `Schema()` methods are named so as not
+// to shadow the qualifiers of imports.
+func buildStructSchemas(b *schemaBuilder, pkg *types.Package, file *ast.File, names []string, imports []ifacetool.Import) ([]*structSchema, error) {
+	specs := make(map[string]*ast.StructType)
+	var order []string
+	for _, d := range file.Decls {
+		gd, ok := d.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, s := range gd.Specs {
+			ts, ok := s.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+			if st, ok := ts.Type.(*ast.StructType); ok {
+				specs[ts.Name.Name] = st
+				order = append(order, ts.Name.Name)
+			}
+		}
+	}
+
+	explicit := len(names) > 0
+	if !explicit {
+		names = order
+	}
+
+	var schemas []*structSchema
+	for _, name := range names {
+		st, ok := specs[name]
+		if !ok {
+			return nil, fmt.Errorf("could not find struct %q", name)
+		}
+
+		obj := pkg.Scope().Lookup(name)
+		if obj == nil {
+			return nil, fmt.Errorf("could not find the type of struct %q", name)
+		}
+		typ := obj.Type().Underlying().(*types.Struct)
+
+		schema, err := buildStructSchema(b, name, receiverName(name, imports), st, typ)
+		if err != nil {
+			return nil, fmt.Errorf("struct %s: %v", name, err)
+		}
+		if len(schema.Fields) == 0 && !explicit {
+			continue
+		}
+		schemas = append(schemas, schema)
+	}
+
+	return schemas, nil
+}
+
+func buildStructSchema(b *schemaBuilder, name, receiver string, st *ast.StructType, typ *types.Struct) (*structSchema, error) {
+	fieldTypes := make(map[string]*types.Var)
+	for i := 0; i < typ.NumFields(); i++ {
+		fieldTypes[typ.Field(i).Name()] = typ.Field(i)
+	}
+
+	schema := &structSchema{
+		Name:     name,
+		Receiver: receiver,
+	}
+
+	for _, field := range st.Fields.List {
+		schemaExpr := fieldSchema(field)
+		if schemaExpr == "" {
+			continue
+		}
+		if len(field.Names) == 0 {
+			return nil, fmt.Errorf("cannot annotate embedded field %s", types.ExprString(field.Type))
+		}
+
+		var tag reflect.StructTag
+		if field.Tag != nil {
+			tag = reflect.StructTag(strings.Trim(field.Tag.Value, "`"))
+		}
+
+		for _, n := range field.Names {
+			value := schema.Receiver + "." + n.Name
+			validator, err := b.bindExpr(value, fieldTypes[n.Name].Type(), schemaExpr, expr.Parse)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %v", n.Name, err)
+			}
+
+			schema.Fields = append(schema.Fields, &schemaField{
+				Name:      fieldName(n.Name, tag),
+				Value:     value,
+				Validator: validator,
+			})
+		}
+	}
+
+	return schema, nil
+}
+
+// receiverName returns the receiver name of the struct name, which defaults
+// to the lowercased initial (e.g. `u` for `User`). A numeric suffix (e.g. `v2`
+// for `Vehicle`) will be appended if the name is used as the qualifier of
+// any import (e.g. `v` of validating).
+func receiverName(name string, imports []ifacetool.Import) string {
+	qualifiers := make(map[string]bool)
+	for _, i := range imports {
+		q := i.Alias
+		if q == "" {
+			q = importName(i.Path)
+		}
+		qualifiers[q] = true
+	}
+
+	r := strings.ToLower(name[:1])
+	receiver := r
+	for n := 2; qualifiers[receiver]; n++ {
+		receiver = r + strconv.Itoa(n)
+	}
+	return receiver
+}
+
+// fieldSchema returns the schema expression of field, which is specified
+// by either the `@schema` annotation or the `validate` tag.
+func fieldSchema(field *ast.Field) string {
+	for _, cg := range []*ast.CommentGroup{field.Doc, field.Comment} {
+		if cg == nil {
+			continue
+		}
+		for _, c := range cg.List {
+			result := reFieldSchema.FindStringSubmatch(strings.TrimPrefix(c.Text, "//"))
+			if len(result) > 0 {
+				return strings.TrimSpace(result[1])
+			}
+		}
+	}
+
+	if field.Tag != nil {
+		tag := reflect.StructTag(strings.Trim(field.Tag.Value, "`"))
+		return tag.Get("validate")
+	}
+
+	return ""
+}
+
+// fieldName returns the name of the field in validation errors, which
+// defaults to the JSON name if any.
+func fieldName(name string, tag reflect.StructTag) string {
+	jsonName := strings.Split(tag.Get("json"), ",")[0]
+	if jsonName != "" && jsonName != "-" {
+		return jsonName
+	}
+	return name
+}
+
+func fileImports(file *ast.File) (imports []ifacetool.Import) {
+	for _, i := range file.Imports {
+		imprt := ifacetool.Import{Path: strings.Trim(i.Path.Value, `"`)}
+		if i.Name != nil {
+			imprt.Alias = i.Name.Name
+		}
+		imports = append(imports, imprt)
+	}
+	sort.Slice(imports, func(i, j int) bool {
+		return imports[i].Path < imports[j].Path
+	})
+	return
+}
+
+// mergeImports appends the imports in b, whose paths are not in a, to a.
+func mergeImports(a, b []ifacetool.Import) []ifacetool.Import {
+	paths := make(map[string]bool)
+	for _, i := range a {
+		paths[i.Path] = true
+	}
+	for _, i := range b {
+		if !paths[i.Path] {
+			a = append(a, i)
+		}
+	}
+	return a
+}
+
+// structGenFilename returns the name of the file generated for the given
+// source file, e.g. `user_schema_gen.go` for `user.go`.
+func structGenFilename(srcFilename string) string {
+	base := strings.TrimSuffix(filepath.Base(srcFilename), ".go")
+	return base + "_schema_gen.go"
+}
+
+// loadFile loads the package to which filename belongs, and returns the
+// package along with the syntax of the file.
+func loadFile(filename string) (*types.Package, *ast.File, error) {
+	fset := token.NewFileSet()
+	pkg, err := loadPackage(fset, filepath.Dir(filename), ".", packages.NeedName|packages.NeedFiles|packages.NeedCompiledGoFiles|packages.NeedSyntax)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Type errors are tolerable, since the package may depend on the
+	// Schema() methods to be generated.
+	conf := types.Config{
+		Importer: sourceImporter(),
+		Error:    func(error) {},
+	}
+	typesPkg, _ := conf.Check(pkg.PkgPath, fset, pkg.Syntax, nil)
+
+	for i, f := range pkg.CompiledGoFiles {
+		if f == filename {
+			return typesPkg, pkg.Syntax[i], nil
+		}
+	}
+	return nil, nil, fmt.Errorf("could not find %s in package %s", filename, pkg.PkgPath)
+}
+
+// sourceImporter returns an importer, which type-checks the imported
+// packages from source. Unlike the export data, the source does not depend
+// on the version of the Go toolchain.
+func sourceImporter() types.ImporterFrom {
+	return importer.ForCompiler(token.NewFileSet(), "source", nil).(types.ImporterFrom)
+}
+
+// dirImporter imports the packages as the source files in dir do.
+type dirImporter struct {
+	from types.ImporterFrom
+	dir  string
+}
+
+func (i dirImporter) Import(path string) (*types.Package, error) {
+	return i.from.ImportFrom(path, i.dir, 0)
+}
+
+// loadPackage loads the package specified by pattern (e.g. `.` or an import
+// path) in dir, which defaults to the current directory if empty. The syntax
+// trees, if any, are positioned in fset.
+func loadPackage(fset *token.FileSet, dir, pattern string, mode packages.LoadMode) (*packages.Package, error) {
+	pkgs, err := packages.Load(&packages.Config{Mode: mode, Dir: dir, Fset: fset}, pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected one package for %s, found %d", pattern, len(pkgs))
+	}
+
+	pkg := pkgs[0]
+	for _, err := range pkg.Errors {
+		// Other errors (e.g. type errors) are tolerable, since the package
+		// may depend on the Schema() methods to be generated.
+		if err.Kind == packages.ParseError {
+			return nil, err
+		}
+	}
+	return pkg, nil
+}
@@ -0,0 +1,192 @@
+package validate
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/protogodev/protogo/parser/ifacetool"
+	"github.com/protogodev/validate/decl"
+)
+
+func TestBuildStructSchemas(t *testing.T) {
+	src := "package user\n\n" +
+		"type User struct {\n" +
+		"	// @schema: len(1, 10)\n" +
+		"	Name string `json:\"name,omitempty\"`\n" +
+		"	Age int `validate:\"xrange(0, 100)\"`\n" +
+		"	Tags []string // @schema: each(nonzero)\n" +
+		"	Note string\n" +
+		"}\n\n" +
+		"type Empty struct {\n" +
+		"	Note string\n" +
+		"}\n\n" +
+		"type Bad struct {\n" +
+		"	// @schema: len(1, 10)\n" +
+		"	Age int\n" +
+		"}\n\n" +
+		"type Vehicle struct {\n" +
+		"	Wheels int // @schema: gt(0)\n" +
+		"}\n"
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "user.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+	pkg, err := new(types.Config).Check("example.com/user", fset, []*ast.File{file}, nil)
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+
+	builtin, err := decl.Parse(decl.BuiltinDecls)
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+	decls := make(map[string][]*decl.Validator)
+	for _, d := range builtin {
+		decls[d.Alias] = append(decls[d.Alias], d)
+	}
+
+	imports := []ifacetool.Import{
+		{Alias: "v", Path: "github.com/RussellLuo/validating/v3"},
+	}
+
+	b := &schemaBuilder{
+		decls: decls,
+		qualifier: func(p *types.Package) string {
+			return ""
+		},
+	}
+
+	tests := []struct {
+		name       string
+		inNames    []string
+		want       []*structSchema
+		wantErrStr string
+	}{
+		{
+			name:    "explicit",
+			inNames: []string{"User", "Empty"},
+			want: []*structSchema{
+				{
+					Name:     "User",
+					Receiver: "u",
+					Fields: []*schemaField{
+						{Name: "name", Value: "u.Name", Validator: `verr.Leaf(verr.Info{Validator: "len", Args: []string{"1", "10"}}, v.LenString(1, 10))`},
+						{Name: "Age", Value: "u.Age", Validator: `verr.Leaf(verr.Info{Validator: "xrange", Args: []string{"0", "100"}}, v.Range[int](0, 100))`},
+						{Name: "Tags", Value: "u.Tags", Validator: `v.Slice(func(_s []string) (_schemas []v.Schema) {
+for _, _elem := range _s {
+_schemas = append(_schemas, v.Value(_elem, verr.Leaf(verr.Info{Validator: "nonzero"}, v.Nonzero[string]())))
+}
+return
+})`},
+					},
+				},
+				{
+					Name:     "Empty",
+					Receiver: "e",
+				},
+			},
+		},
+		{
+			name:    "receiver colliding with qualifier",
+			inNames: []string{"Vehicle"},
+			want: []*structSchema{
+				{
+					Name:     "Vehicle",
+					Receiver: "v2",
+					Fields: []*schemaField{
+						{Name: "Wheels", Value: "v2.Wheels", Validator: `verr.Leaf(verr.Info{Validator: "gt", Args: []string{"0"}}, v.Gt[int](0))`},
+					},
+				},
+			},
+		},
+		{
+			name:       "bad",
+			inNames:    nil, // All annotated structs, including Bad.
+			wantErrStr: "struct Bad: field Age: cannot use validator `len` on type *types.Basic",
+		},
+		{
+			name:       "not found",
+			inNames:    []string{"Unknown"},
+			wantErrStr: `could not find struct "Unknown"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildStructSchemas(b, pkg, file, tt.inNames, imports)
+			if err != nil {
+				if err.Error() != tt.wantErrStr {
+					t.Fatalf("Err: got (%#v), want (%#v)", err.Error(), tt.wantErrStr)
+				}
+				return
+			}
+			if tt.wantErrStr != "" {
+				t.Fatalf("Err: got (%#v), want (%#v)", "", tt.wantErrStr)
+			}
+
+			if !cmp.Equal(got, tt.want) {
+				diff := cmp.Diff(got, tt.want)
+				t.Errorf("Want - Got: %s", diff)
+			}
+		})
+	}
+}
+
+func TestStructGenerator_Generate(t *testing.T) {
+	src := "package vehicle\n\n" +
+		"type Vehicle struct {\n" +
+		"	Wheels int // @schema: gt(0)\n" +
+		"}\n"
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "vehicle.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+	pkg, err := new(types.Config).Check("example.com/vehicle", fset, []*ast.File{file}, nil)
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+
+	g := &StructGenerator{Formatted: true}
+	f, err := g.Generate(pkg, file, "vehicle_schema_gen.go")
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+
+	// The receiver must not shadow the qualifier `v` of validating.
+	want := "func (v2 Vehicle) Schema() v.Schema {\n" +
+		"	return v.Schema{\n" +
+		"		v.F(\"Wheels\", v2.Wheels): verr.Leaf(verr.Info{Validator: \"gt\", Args: []string{\"0\"}}, v.Gt[int](0)),\n" +
+		"	}\n" +
+		"}\n"
+	if got := string(f.Content); !strings.HasSuffix(got, want) {
+		t.Errorf("Content: got (%s), want suffix (%s)", got, want)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	filename, err := filepath.Abs("examples/usersvc/service.go")
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+
+	pkg, file, err := loadFile(filename)
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+	if got, want := pkg.Path(), "github.com/protogodev/validate/examples/usersvc"; got != want {
+		t.Errorf("Path: got (%q), want (%q)", got, want)
+	}
+	if pkg.Scope().Lookup("User") == nil || file.Name.Name != "usersvc" {
+		t.Errorf("could not find struct User in %s", filename)
+	}
+}
@@ -0,0 +1,37 @@
+package fixture
+
+import (
+	"context"
+)
+
+// Service is a fixture for the generated tests (see TestGenerator_GenerateTests).
+type Service interface {
+	// @schema:
+	//   name: len(1, 10).msg("bad name") && !in("admin", "root")
+	//   age: xrange(0, 150)
+	//   email: zero || email
+	Create(ctx context.Context, name string, age int, email string) (id int, err error)
+
+	// @schema:
+	//   min: gte(0)
+	//   max: gt(min) && lte(100)
+	//   pattern: match(`^[a-z]*$`)
+	//   data: runecnt(0, 8)
+	Search(min, max int, pattern string, data []byte) error
+
+	// @schema:
+	//   ip: zero || ip
+	//   day: time("2006-01-02")
+	//   ratio: gte(0.5) && lt(2.5)
+	Track(ip, day string, ratio float64) error
+
+	// @schema:
+	//   tags: each(len(1, 5))
+	Tag(tags []string) error
+
+	// @schema:
+	//   name: nonzero
+	// @assert:
+	//   name: name != "x"
+	Rename(name string) error
+}
@@ -0,0 +1,770 @@
+package validate
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"go/constant"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	v "github.com/RussellLuo/validating/v3"
+	"github.com/protogodev/protogo/generator"
+	"github.com/protogodev/protogo/parser/ifacetool"
+	"github.com/protogodev/validate/expr"
+	"github.com/protogodev/validate/runtime"
+)
+
+//go:embed template_test.go.tmpl
+var testTemplate string
+
+// maxSampleLen is the maximum length of the sample strings (or slices), which
+// skips the unreachable boundaries such as the default maximum of `len`.
+const maxSampleLen = 1024
+
+// sampleTime is the time, which is formatted per the layout of `time`.
+var sampleTime = time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+
+// GenerateTests generates the table-driven tests (if Tests is set) and the
+// fuzz targets (if Fuzz is set) of the validation middleware for the
+// interface.
+//
+// For each method, the test cases are derived from the boundaries of the
+// validators of its params (e.g. a 10- and an 11-char string for `len(0, 10)`),
+// and whether each case is expected to fail is determined by interpreting
+// the schema expressions at runtime (see package runtime). The fuzz targets
+// are seeded from the same cases, and check that the middleware agrees with
+// the Go expressions translated from the schema expressions (see oracle) on
+// any input. The methods, whose schemas cannot be interpreted (e.g. the ones
+// with `@assert` or custom validators), or whose params are not of the
+// unnamed basic types (or the slices or pointers of them), are skipped with
+// warnings. So are the fuzz targets of the methods, whose params having
+// validators are not supported by fuzzing (e.g. `[]string`), or whose schema
+// expressions cannot be translated (e.g. `len(max: 10)`). Each skip is also
+// noted in the generated file.
+func (g *Generator) GenerateTests(data *ifacetool.Data) (*generator.File, error) {
+	// The warnings have been printed by Generate.
+	imports, builder, _, err := g.prepare(data)
+	if err != nil {
+		return nil, err
+	}
+	imports = mergeImports(imports, []ifacetool.Import{
+		{Path: "context"},
+		{Path: "strings"},
+		{Path: "testing"},
+	})
+	imports = mergeImports(imports, oracleImports)
+
+	var tests []*methodTests
+	for _, method := range data.Methods {
+		t, err := buildMethodTests(builder, method, ParseDoc(method.Doc), imports)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %s: %v (skipped)\n", method.Name, err)
+			// Also note the skip in the generated file.
+			tests = append(tests, &methodTests{Method: method, Skipped: skipReason(err)})
+			continue
+		}
+		if t == nil {
+			continue
+		}
+		if !g.Tests {
+			t.Cases = nil
+		}
+		if !g.Fuzz {
+			t.Fuzz = nil
+		} else if t.Fuzz == nil {
+			fmt.Fprintf(os.Stderr, "warning: %s: %v (fuzz target skipped)\n", method.Name, t.fuzzErr)
+			t.FuzzSkipped = skipReason(t.fuzzErr)
+		}
+		tests = append(tests, t)
+	}
+
+	tmplData := struct {
+		Imports []ifacetool.Import
+		Data    *ifacetool.Data
+		Tests   []*methodTests
+	}{
+		Imports: imports,
+		Data:    data,
+		Tests:   tests,
+	}
+
+	return generator.Generate(testTemplate, tmplData, generator.Options{
+		Funcs: map[string]interface{}{
+			"emptyValues": func(params []*ifacetool.Param) string {
+				var values []string
+				for _, p := range params {
+					values = append(values, emptyValue(p))
+				}
+				return strings.Join(values, ", ")
+			},
+			"join": strings.Join,
+		},
+		Formatted:      g.Formatted,
+		TargetFileName: filepath.Join(g.OutDir, "validate_gen_test.go"),
+	})
+}
+
+// methodTests is the table-driven test, along with the fuzz target, of a
+// method.
+type methodTests struct {
+	Method *ifacetool.Method
+	Params []*ifacetool.Param // Excluding the context.
+	Cases  []*testCase
+	Fuzz   *fuzzTarget // Nil if the method cannot be fuzzed.
+
+	// Skipped and FuzzSkipped are the reasons why the method cannot be
+	// tested or fuzzed, which are noted in the generated file.
+	Skipped     string
+	FuzzSkipped string
+
+	fuzzErr error // The reason why the method cannot be fuzzed.
+}
+
+// skipReason returns the reason of err, which fits in a line comment.
+func skipReason(err error) string {
+	return strings.Join(strings.Fields(err.Error()), " ")
+}
+
+// Call returns the call expression of the method, whose arguments are taken
+// from the test case `tt`, along with the results.
+func (t *methodTests) Call() string {
+	var args []string
+	for _, p := range t.Method.Params {
+		switch {
+		case p.TypeString == "context.Context":
+			args = append(args, "context.Background()")
+		case p.Variadic:
+			args = append(args, "tt.args."+p.Name+"...")
+		default:
+			args = append(args, "tt.args."+p.Name)
+		}
+	}
+
+	results := make([]string, len(t.Method.Returns))
+	for i := range results {
+		results[i] = "_"
+	}
+	results[len(results)-1] = "err"
+
+	return fmt.Sprintf("%s := svc.%s(%s)", strings.Join(results, ", "), t.Method.Name, strings.Join(args, ", "))
+}
+
+// FuzzCall returns the call expression of the method, whose arguments are
+// taken from the fuzz arguments (or the zero values), along with the results.
+func (t *methodTests) FuzzCall() string {
+	var args []string
+	for _, p := range t.Method.Params {
+		switch {
+		case p.TypeString == "context.Context":
+			args = append(args, "context.Background()")
+		case p.Variadic:
+			args = append(args, p.Name+"...")
+		default:
+			args = append(args, p.Name)
+		}
+	}
+
+	results := make([]string, len(t.Method.Returns))
+	for i := range results {
+		results[i] = "_"
+	}
+	results[len(results)-1] = "err"
+
+	return fmt.Sprintf("%s := svc.%s(%s)", strings.Join(results, ", "), t.Method.Name, strings.Join(args, ", "))
+}
+
+// fuzzTarget is the fuzz target of a method.
+type fuzzTarget struct {
+	Args  []*ifacetool.Param // The fuzz arguments.
+	Vars  []*ifacetool.Param // The other params, which are always zero.
+	Want  string             // The Go expression telling whether the params are valid.
+	Seeds [][]string         // The seed corpus.
+}
+
+// fuzzReserved are the identifiers used by the generated fuzz targets.
+var fuzzReserved = map[string]bool{
+	"t": true, "f": true, "svc": true, "err": true, "want": true,
+}
+
+// testCase is a case of the table-driven test.
+type testCase struct {
+	Name    string
+	Args    []testArg
+	WantErr bool
+}
+
+// key returns the key identifying the arguments of the case.
+func (c *testCase) key() string {
+	var args []string
+	for _, a := range c.Args {
+		args = append(args, a.Name+": "+a.Value)
+	}
+	return strings.Join(args, ", ")
+}
+
+type testArg struct {
+	Name  string
+	Value string // The Go literal.
+}
+
+// testParam is a param having validators, along with the candidate values.
+type testParam struct {
+	*ifacetool.Param
+	Typ        reflect.Type
+	Validators []expr.Validator
+	Rules      []*runtime.Rule
+	Samples    []reflect.Value
+}
+
+// buildMethodTests builds the test cases, along with the fuzz target, for
+// the given method, whose annotations are annos. The packages referenced by
+// the fuzz target are qualified per imports. It returns nil if the method
+// has no `@schema`, or an error telling why the method cannot be tested.
+func buildMethodTests(b *schemaBuilder, method *ifacetool.Method, annos map[string][]Option, imports []ifacetool.Import) (*methodTests, error) {
+	if len(annos["schema"]) == 0 {
+		return nil, nil
+	}
+	if len(annos["assert"]) > 0 {
+		return nil, errors.New("cannot test the params against @assert")
+	}
+	if n := len(method.Returns); n == 0 || method.Returns[n-1].TypeString != "error" {
+		return nil, errors.New("cannot test a method whose last result is not an error")
+	}
+
+	params, ctx := methodParams(method)
+	b = b.WithContext(ctx).WithScope(params)
+
+	scope := make(map[string]reflect.Type)
+	for _, p := range params {
+		if p.Name == "" || p.Name == "_" {
+			return nil, errors.New("cannot test a method with unnamed params")
+		}
+		if typ, ok := reflectType(p.Type); ok {
+			scope[p.Name] = typ
+		}
+	}
+
+	// Collect the params having validators, in the order of the annotations.
+	var tps []*testParam
+	byName := make(map[string]*testParam)
+	registry := runtime.New()
+	for _, opt := range annos["schema"] {
+		p := findParam(params, opt.K)
+		if p == nil {
+			// Unknown keys or nested keys (e.g. `user.Name`).
+			return nil, fmt.Errorf("cannot test %s, which is not a param", opt.K)
+		}
+		typ, ok := scope[opt.K]
+		if !ok {
+			return nil, fmt.Errorf("cannot test %s (only the params of the unnamed basic types, or the slices or pointers of them, are supported)", opt.K)
+		}
+
+		validator, err := b.bind(p.Name, p.Type, opt.V, expr.Parse)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", opt.K, err)
+		}
+		rule, err := registry.Compile(opt.V, typ, scope)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", opt.K, err)
+		}
+
+		tp, ok := byName[p.Name]
+		if !ok {
+			tp = &testParam{Param: p}
+			tps = append(tps, tp)
+			byName[p.Name] = tp
+		}
+		tp.Typ = typ
+		tp.Validators = append(tp.Validators, validator)
+		tp.Rules = append(tp.Rules, rule)
+	}
+
+	// Find a valid value for each param, which makes up the baseline. The
+	// arguments referencing other params (e.g. `gt(min)`) are evaluated to
+	// the values found so far.
+	env := make(map[string]interface{})
+	for name, typ := range scope {
+		env[name] = reflect.Zero(typ).Interface()
+	}
+	eval := func(arg string) (constant.Value, bool) {
+		if value, ok := env[arg]; ok {
+			return constValue(reflect.ValueOf(value))
+		}
+		return expr.EvalConst(arg)
+	}
+	for _, tp := range tps {
+		for _, validator := range tp.Validators {
+			tp.Samples = append(tp.Samples, samples(validator, tp.Typ, eval)...)
+		}
+		for _, s := range tp.Samples {
+			env[tp.Name] = s.Interface()
+			if ok, _ := validParams(env, tp); ok {
+				break
+			}
+		}
+	}
+	if ok, err := validParams(env, tps...); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, errors.New("cannot find the valid params, from which the test cases are derived")
+	}
+
+	// Each case must differ from the others in the arguments, including the
+	// baseline (e.g. a sample, which happens to be the baseline value).
+	t := &methodTests{Method: method, Params: params}
+	valid := &testCase{Name: "valid", Args: testArgs(env, tps, b.qualifier)}
+	t.Cases = append(t.Cases, valid)
+	seen := map[string]bool{valid.key(): true}
+	envs := []map[string]interface{}{env}
+	for _, tp := range tps {
+		for _, s := range tp.Samples {
+			caseEnv := make(map[string]interface{}, len(env))
+			for name, value := range env {
+				caseEnv[name] = value
+			}
+			caseEnv[tp.Name] = s.Interface()
+
+			c := &testCase{
+				Name: tp.Name + "=" + literal(s, tp.Type, b.qualifier),
+				Args: testArgs(caseEnv, tps, b.qualifier),
+			}
+			if seen[c.key()] {
+				continue
+			}
+			seen[c.key()] = true
+
+			ok, err := validParams(caseEnv, tps...)
+			if err != nil {
+				return nil, err
+			}
+			c.WantErr = !ok
+			t.Cases = append(t.Cases, c)
+			envs = append(envs, caseEnv)
+		}
+	}
+
+	t.Fuzz, t.fuzzErr = buildFuzzTarget(newOracle(b.decls, imports), params, scope, tps, envs, annos["schema"], b.qualifier)
+	return t, nil
+}
+
+// buildFuzzTarget builds the fuzz target, which is seeded from envs, for the
+// method having the given params. The validity of the params is told by the
+// oracle o, instead of the code generator. It returns an error if the method
+// cannot be fuzzed.
+func buildFuzzTarget(o *oracle, params []*ifacetool.Param, scope map[string]reflect.Type, tps []*testParam, envs []map[string]interface{}, opts []Option, qualifier types.Qualifier) (*fuzzTarget, error) {
+	f := new(fuzzTarget)
+	for _, p := range params {
+		if fuzzable(p.Type) && !p.Variadic {
+			f.Args = append(f.Args, p)
+		} else {
+			f.Vars = append(f.Vars, p)
+		}
+	}
+	for _, tp := range tps {
+		if !fuzzable(tp.Type) || tp.Variadic {
+			return nil, fmt.Errorf("cannot fuzz %s (only the params of the basic types and []byte are supported)", tp.Name)
+		}
+	}
+
+	var conds []string
+	for _, opt := range opts {
+		p := findParam(params, opt.K)
+		cond, err := o.Expr(p.Name, p.Type, opt.V)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", opt.K, err)
+		}
+		conds = append(conds, cond)
+	}
+	f.Want = strings.Join(conds, " && ")
+
+	for _, p := range params {
+		if fuzzReserved[p.Name] || o.Shadowed(p.Name) {
+			return nil, fmt.Errorf("cannot fuzz %s, whose name is reserved by the fuzz target", p.Name)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, env := range envs {
+		var seed []string
+		for _, p := range f.Args {
+			value := reflect.Zero(scope[p.Name])
+			if x, ok := env[p.Name]; ok {
+				value = reflect.ValueOf(x)
+			}
+			seed = append(seed, fuzzLiteral(value, p.Type, qualifier))
+		}
+		if key := strings.Join(seed, ", "); !seen[key] {
+			seen[key] = true
+			f.Seeds = append(f.Seeds, seed)
+		}
+	}
+
+	return f, nil
+}
+
+// fuzzable reports whether typ is supported by Go fuzzing.
+func fuzzable(typ types.Type) bool {
+	switch t := typ.(type) {
+	case *types.Basic:
+		return t.Info()&(types.IsBoolean|types.IsNumeric|types.IsString) != 0 && t.Info()&types.IsComplex == 0
+	case *types.Slice:
+		b, ok := t.Elem().(*types.Basic)
+		return ok && b.Kind() == types.Uint8
+	}
+	return false
+}
+
+// fuzzLiteral returns the Go literal of value, whose type is typ, which is
+// typed exactly as required by testing.F.Add.
+func fuzzLiteral(value reflect.Value, typ types.Type, qualifier types.Qualifier) string {
+	lit := literal(value, typ, qualifier)
+	if b, ok := typ.(*types.Basic); ok && b.Info()&types.IsNumeric != 0 {
+		return fmt.Sprintf("%s(%s)", types.TypeString(typ, qualifier), lit)
+	}
+	if _, ok := typ.(*types.Slice); ok && value.IsNil() {
+		return fmt.Sprintf("%s(nil)", types.TypeString(typ, qualifier))
+	}
+	return lit
+}
+
+// validParams reports whether the params tps in env are valid.
+func validParams(env map[string]interface{}, tps ...*testParam) (bool, error) {
+	schema := make(v.Schema)
+	for _, tp := range tps {
+		for _, rule := range tp.Rules {
+			validator, err := rule.Validator(context.Background(), env)
+			if err != nil {
+				return false, err
+			}
+			schema[v.F(tp.Name, env[tp.Name])] = validator
+		}
+	}
+	return len(v.Validate(schema)) == 0, nil
+}
+
+// testArgs returns the arguments of a test case, whose values are taken
+// from env.
+func testArgs(env map[string]interface{}, tps []*testParam, qualifier types.Qualifier) (args []testArg) {
+	for _, tp := range tps {
+		value := reflect.ValueOf(env[tp.Name])
+		if value.IsZero() {
+			continue
+		}
+		args = append(args, testArg{
+			Name:  tp.Name,
+			Value: literal(value, tp.Type, qualifier),
+		})
+	}
+	return
+}
+
+func findParam(params []*ifacetool.Param, name string) *ifacetool.Param {
+	for _, p := range params {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// reflectType returns the reflect type of typ, which is an unnamed basic
+// type, or a slice (or a pointer) of such types.
+func reflectType(typ types.Type) (reflect.Type, bool) {
+	switch t := typ.(type) {
+	case *types.Basic:
+		switch t.Kind() {
+		case types.Bool:
+			return reflect.TypeOf(false), true
+		case types.Int:
+			return reflect.TypeOf(int(0)), true
+		case types.Int8:
+			return reflect.TypeOf(int8(0)), true
+		case types.Int16:
+			return reflect.TypeOf(int16(0)), true
+		case types.Int32:
+			return reflect.TypeOf(int32(0)), true
+		case types.Int64:
+			return reflect.TypeOf(int64(0)), true
+		case types.Uint:
+			return reflect.TypeOf(uint(0)), true
+		case types.Uint8:
+			return reflect.TypeOf(uint8(0)), true
+		case types.Uint16:
+			return reflect.TypeOf(uint16(0)), true
+		case types.Uint32:
+			return reflect.TypeOf(uint32(0)), true
+		case types.Uint64:
+			return reflect.TypeOf(uint64(0)), true
+		case types.Float32:
+			return reflect.TypeOf(float32(0)), true
+		case types.Float64:
+			return reflect.TypeOf(float64(0)), true
+		case types.String:
+			return reflect.TypeOf(""), true
+		}
+	case *types.Slice:
+		if elem, ok := reflectType(t.Elem()); ok {
+			return reflect.SliceOf(elem), true
+		}
+	case *types.Pointer:
+		if elem, ok := reflectType(t.Elem()); ok {
+			return reflect.PointerTo(elem), true
+		}
+	}
+	return nil, false
+}
+
+// evalFunc evaluates the argument of a leaf validator to a constant.
+type evalFunc func(arg string) (constant.Value, bool)
+
+// samples returns the candidate values of type typ for the validator,
+// which are around the boundaries of its leaf validators. The zero value
+// is always the first candidate.
+func samples(validator expr.Validator, typ reflect.Type, eval evalFunc) []reflect.Value {
+	values := []reflect.Value{reflect.Zero(typ)}
+
+	switch x := validator.(type) {
+	case *expr.PointerValidator:
+		if typ.Kind() != reflect.Pointer {
+			return append(values, samples(x.Inner, typ, eval)...)
+		}
+		if x.Inner == nil {
+			return append(values, reflect.New(typ.Elem()))
+		}
+		for _, s := range samples(x.Inner, typ.Elem(), eval) {
+			ptr := reflect.New(typ.Elem())
+			ptr.Elem().Set(s)
+			values = append(values, ptr)
+		}
+	case *expr.LogicValidator:
+		values = append(values, samples(x.Left, typ, eval)...)
+		if x.Right != nil {
+			values = append(values, samples(x.Right, typ, eval)...)
+		}
+	case *expr.ElemValidator:
+		if typ.Kind() != reflect.Slice {
+			break
+		}
+		for _, s := range samples(x.Inner, typ.Elem(), eval) {
+			values = append(values, reflect.Append(reflect.MakeSlice(typ, 0, 1), s))
+		}
+	case *expr.LeafValidator:
+		values = append(values, leafSamples(x, typ, eval)...)
+	}
+
+	return values
+}
+
+// leafSamples returns the candidate values of type typ for the builtin leaf
+// validator x, e.g. a 10- and an 11-char string for `len(0, 10)`.
+func leafSamples(x *expr.LeafValidator, typ reflect.Type, eval evalFunc) (values []reflect.Value) {
+	d := x.MatchedDecl()
+	if d == nil {
+		return nil
+	}
+
+	add := func(xs ...interface{}) {
+		for _, x := range xs {
+			if value, ok := sampleValue(typ, x); ok {
+				values = append(values, value)
+			}
+		}
+	}
+
+	switch d.Entry() {
+	case "v.Nonzero", "v.Zero":
+		add(int64(1), "a", true)
+	case "v.LenString", "v.LenSlice", "v.RuneCount":
+		for _, arg := range x.Args {
+			c, ok := eval(arg)
+			if !ok {
+				continue
+			}
+			n, ok := constant.Int64Val(c)
+			if !ok {
+				continue
+			}
+			for _, m := range []int64{n - 1, n, n + 1} {
+				if m < 0 || m > maxSampleLen {
+					continue
+				}
+				if typ.Kind() == reflect.Slice && typ.Elem().Kind() != reflect.Uint8 {
+					values = append(values, reflect.MakeSlice(typ, int(m), int(m)))
+				} else {
+					add(strings.Repeat("a", int(m)))
+				}
+			}
+		}
+	case "v.Eq", "v.Ne", "v.Gt", "v.Gte", "v.Lt", "v.Lte", "v.Range", "v.In", "v.Nin":
+		for _, arg := range x.Args {
+			if c, ok := eval(arg); ok {
+				add(around(c)...)
+			}
+		}
+	case "vext.Email":
+		add("user@example.com", "user@example", "user")
+	case "vext.IP":
+		add("127.0.0.1", "::1", "127.0.0.256")
+	case "vext.Time":
+		if len(x.Args) > 0 {
+			if c, ok := eval(x.Args[0]); ok && c.Kind() == constant.String {
+				add(sampleTime.Format(constant.StringVal(c)))
+			}
+		}
+		add("invalid")
+	}
+
+	return values
+}
+
+// constValue returns the constant of the basic value.
+func constValue(value reflect.Value) (constant.Value, bool) {
+	switch value.Kind() {
+	case reflect.Bool:
+		return constant.MakeBool(value.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return constant.MakeInt64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return constant.MakeUint64(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return constant.MakeFloat64(value.Float()), true
+	case reflect.String:
+		return constant.MakeString(value.String()), true
+	}
+	return nil, false
+}
+
+// around returns the values around the constant c, e.g. 9, 10 and 11 for 10.
+func around(c constant.Value) []interface{} {
+	switch c.Kind() {
+	case constant.Bool:
+		return []interface{}{true, false}
+	case constant.String:
+		s := constant.StringVal(c)
+		if s == "" {
+			return []interface{}{s, "a"}
+		}
+		return []interface{}{s[:len(s)-1], s, s + "a"}
+	case constant.Int:
+		if x, ok := constant.Int64Val(c); ok {
+			return []interface{}{x - 1, x, x + 1}
+		}
+		if x, ok := constant.Uint64Val(c); ok {
+			return []interface{}{x - 1, x, x + 1}
+		}
+	case constant.Float:
+		if x, ok := constant.Float64Val(c); ok {
+			return []interface{}{x - 1, x, x + 1}
+		}
+	}
+	return nil
+}
+
+// sampleValue converts x (an int64, a uint64, a float64, a string or a bool)
+// into a value of type typ. It reports false if the conversion is impossible
+// or inexact (e.g. an overflow).
+func sampleValue(typ reflect.Type, x interface{}) (reflect.Value, bool) {
+	value := reflect.New(typ).Elem()
+
+	switch x := x.(type) {
+	case int64:
+		switch typ.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if value.OverflowInt(x) {
+				return value, false
+			}
+			value.SetInt(x)
+			return value, true
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if x < 0 || value.OverflowUint(uint64(x)) {
+				return value, false
+			}
+			value.SetUint(uint64(x))
+			return value, true
+		case reflect.Float32, reflect.Float64:
+			value.SetFloat(float64(x))
+			return value, true
+		}
+	case uint64:
+		switch typ.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if value.OverflowUint(x) {
+				return value, false
+			}
+			value.SetUint(x)
+			return value, true
+		}
+	case float64:
+		switch typ.Kind() {
+		case reflect.Float32, reflect.Float64:
+			if value.OverflowFloat(x) {
+				return value, false
+			}
+			value.SetFloat(x)
+			return value, true
+		}
+	case string:
+		switch {
+		case typ.Kind() == reflect.String:
+			value.SetString(x)
+			return value, true
+		case typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8:
+			value.SetBytes([]byte(x))
+			return value, true
+		}
+	case bool:
+		if typ.Kind() == reflect.Bool {
+			value.SetBool(x)
+			return value, true
+		}
+	}
+
+	return value, false
+}
+
+// literal returns the Go literal of value, whose type is typ.
+func literal(value reflect.Value, typ types.Type, qualifier types.Qualifier) string {
+	switch t := typ.(type) {
+	case *types.Basic:
+		switch {
+		case t.Info()&types.IsString != 0:
+			s := value.String()
+			if len(s) > 16 && strings.Count(s, s[:1]) == len(s) {
+				return fmt.Sprintf("strings.Repeat(%q, %d)", s[:1], len(s))
+			}
+			return strconv.Quote(s)
+		case t.Info()&types.IsFloat != 0:
+			return strconv.FormatFloat(value.Float(), 'g', -1, 64)
+		default:
+			return fmt.Sprint(value.Interface())
+		}
+	case *types.Slice:
+		if value.IsNil() {
+			return "nil"
+		}
+		if b, ok := t.Elem().(*types.Basic); ok && b.Kind() == types.Uint8 {
+			return fmt.Sprintf("%s(%s)", types.TypeString(t, qualifier), literal(reflect.ValueOf(string(value.Bytes())), types.Typ[types.String], qualifier))
+		}
+		if value.Len() > 16 {
+			return fmt.Sprintf("make(%s, %d)", types.TypeString(t, qualifier), value.Len())
+		}
+		elems := make([]string, value.Len())
+		for i := range elems {
+			elems[i] = literal(value.Index(i), t.Elem(), qualifier)
+		}
+		return fmt.Sprintf("%s{%s}", types.TypeString(t, qualifier), strings.Join(elems, ", "))
+	case *types.Pointer:
+		if value.IsNil() {
+			return "nil"
+		}
+		return fmt.Sprintf("validatePtr[%s](%s)", types.TypeString(t.Elem(), qualifier), literal(value.Elem(), t.Elem(), qualifier))
+	}
+	return fmt.Sprint(value.Interface())
+}
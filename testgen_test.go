@@ -0,0 +1,451 @@
+package validate
+
+import (
+	"fmt"
+	"go/types"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/protogodev/protogo/generator"
+	"github.com/protogodev/protogo/parser/ifacetool"
+	"github.com/protogodev/validate/decl"
+)
+
+func TestBuildMethodTests(t *testing.T) {
+	builtin, err := decl.Parse(decl.BuiltinDecls)
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+	decls := make(map[string][]*decl.Validator)
+	for _, d := range builtin {
+		decls[d.Alias] = append(decls[d.Alias], d)
+	}
+	b := &schemaBuilder{
+		decls: decls,
+		qualifier: func(p *types.Package) string {
+			return p.Name()
+		},
+	}
+
+	pkg := types.NewPackage("example.com/user", "user")
+	user := types.NewNamed(types.NewTypeName(0, pkg, "User", nil), types.NewStruct(nil, nil), nil)
+	method := &ifacetool.Method{
+		Name: "Create",
+		Params: []*ifacetool.Param{
+			{Name: "ctx", TypeString: "context.Context"},
+			{Name: "name", TypeString: "string", Type: types.Typ[types.String]},
+			{Name: "answer", TypeString: "*string", Type: types.NewPointer(types.Typ[types.String])},
+			{Name: "min", TypeString: "int", Type: types.Typ[types.Int]},
+			{Name: "max", TypeString: "int", Type: types.Typ[types.Int]},
+			{Name: "user", TypeString: "user.User", Type: user},
+		},
+		Returns: []*ifacetool.Param{
+			{Name: "err", TypeString: "error", Type: types.Universe.Lookup("error").Type()},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		in         map[string][]Option
+		want       []string // The test cases in the form of `name args: wantErr`.
+		wantErrStr string
+	}{
+		{
+			name: "cases",
+			in: map[string][]Option{
+				"schema": {
+					{K: "name", V: "len(1, 3)"},
+					{K: "answer", V: `optional && in("Y")`},
+					{K: "max", V: "gt(min)"},
+				},
+			},
+			want: []string{
+				`valid [{name "a"} {max 1}]: false`,
+				`name="" [{max 1}]: true`,
+				`name="aa" [{name "aa"} {max 1}]: false`,
+				`name="aaa" [{name "aaa"} {max 1}]: false`,
+				`name="aaaa" [{name "aaaa"} {max 1}]: true`,
+				`answer=validatePtr[string]("") [{name "a"} {answer validatePtr[string]("")} {max 1}]: true`,
+				`answer=validatePtr[string]("Y") [{name "a"} {answer validatePtr[string]("Y")} {max 1}]: false`,
+				`answer=validatePtr[string]("Ya") [{name "a"} {answer validatePtr[string]("Ya")} {max 1}]: true`,
+				`max=0 [{name "a"}]: true`,
+				`max=-1 [{name "a"} {max -1}]: true`,
+			},
+		},
+		{
+			name: "assert",
+			in: map[string][]Option{
+				"schema": {{K: "name", V: "len(1, 3)"}},
+				"assert": {{K: "name", V: `name != "x"`}},
+			},
+			wantErrStr: "cannot test the params against @assert",
+		},
+		{
+			name: "unsupported type",
+			in: map[string][]Option{
+				"schema": {{K: "user", V: "nonzero"}},
+			},
+			wantErrStr: "cannot test user (only the params of the unnamed basic types, or the slices or pointers of them, are supported)",
+		},
+		{
+			name: "nested field",
+			in: map[string][]Option{
+				"schema": {{K: "user.Name", V: "nonzero"}},
+			},
+			wantErrStr: "cannot test user.Name, which is not a param",
+		},
+		{
+			name: "no schema",
+			in:   map[string][]Option{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mt, err := buildMethodTests(b, method, tt.in, nil)
+			if err != nil {
+				if err.Error() != tt.wantErrStr {
+					t.Fatalf("Err: got (%#v), want (%#v)", err.Error(), tt.wantErrStr)
+				}
+				return
+			}
+			if tt.wantErrStr != "" {
+				t.Fatalf("Err: got (%#v), want (%#v)", "", tt.wantErrStr)
+			}
+
+			var got []string
+			if mt != nil {
+				got = []string{}
+				for _, c := range mt.Cases {
+					got = append(got, fmt.Sprintf("%s %v: %v", c.Name, c.Args, c.WantErr))
+				}
+			}
+			if !cmp.Equal(got, tt.want) {
+				diff := cmp.Diff(tt.want, got)
+				t.Errorf("Want - Got: %s", diff)
+			}
+		})
+	}
+}
+
+func TestLiteral(t *testing.T) {
+	str := types.Typ[types.String]
+	tests := []struct {
+		name   string
+		inV    interface{}
+		inType types.Type
+		want   string
+	}{
+		{
+			name:   "string",
+			inV:    "abc",
+			inType: str,
+			want:   `"abc"`,
+		},
+		{
+			name:   "long string",
+			inV:    strings.Repeat("a", 20),
+			inType: str,
+			want:   `strings.Repeat("a", 20)`,
+		},
+		{
+			name:   "float",
+			inV:    1.5,
+			inType: types.Typ[types.Float64],
+			want:   "1.5",
+		},
+		{
+			name:   "slice",
+			inV:    []string{"a", "b"},
+			inType: types.NewSlice(str),
+			want:   `[]string{"a", "b"}`,
+		},
+		{
+			name:   "bytes",
+			inV:    []byte("ab"),
+			inType: types.NewSlice(types.Universe.Lookup("byte").Type()),
+			want:   `[]byte("ab")`,
+		},
+		{
+			name:   "long slice",
+			inV:    make([]string, 20),
+			inType: types.NewSlice(str),
+			want:   `make([]string, 20)`,
+		},
+		{
+			name:   "nil pointer",
+			inV:    (*int)(nil),
+			inType: types.NewPointer(types.Typ[types.Int]),
+			want:   "nil",
+		},
+		{
+			name:   "pointer",
+			inV:    new(int),
+			inType: types.NewPointer(types.Typ[types.Int]),
+			want:   "validatePtr[int](0)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := literal(reflect.ValueOf(tt.inV), tt.inType, nil)
+			if got != tt.want {
+				t.Errorf("Literal: got (%s), want (%s)", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildFuzzTarget(t *testing.T) {
+	builtin, err := decl.Parse(decl.BuiltinDecls)
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+	decls := make(map[string][]*decl.Validator)
+	for _, d := range builtin {
+		decls[d.Alias] = append(decls[d.Alias], d)
+	}
+	b := &schemaBuilder{decls: decls}
+
+	method := &ifacetool.Method{
+		Name: "Create",
+		Params: []*ifacetool.Param{
+			{Name: "name", TypeString: "string", Type: types.Typ[types.String]},
+			{Name: "age", TypeString: "*int", Type: types.NewPointer(types.Typ[types.Int])},
+			{Name: "tags", TypeString: "[]string", Type: types.NewSlice(types.Typ[types.String])},
+		},
+		Returns: []*ifacetool.Param{
+			{Name: "err", TypeString: "error", Type: types.Universe.Lookup("error").Type()},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		in         []Option
+		want       *fuzzTarget
+		wantErrStr string
+	}{
+		{
+			name: "fuzzable",
+			in:   []Option{{K: "name", V: `in("a", "b")`}},
+			want: &fuzzTarget{
+				Args:  []*ifacetool.Param{method.Params[0]},
+				Vars:  []*ifacetool.Param{method.Params[1], method.Params[2]},
+				Want:  `(name == "a" || name == "b")`,
+				Seeds: [][]string{{`"a"`}, {`""`}, {`"aa"`}, {`"b"`}, {`"ba"`}},
+			},
+		},
+		{
+			name: "multiple schemas",
+			in: []Option{
+				{K: "name", V: `len(1, 2).msg("bad")`},
+				{K: "name", V: `!eq("b")`},
+			},
+			want: &fuzzTarget{
+				Args:  []*ifacetool.Param{method.Params[0]},
+				Vars:  []*ifacetool.Param{method.Params[1], method.Params[2]},
+				Want:  `(len(name) >= 1 && len(name) <= 2) && !(!(name != "b"))`,
+				Seeds: [][]string{{`"a"`}, {`""`}, {`"aa"`}, {`"aaa"`}, {`"b"`}, {`"ba"`}},
+			},
+		},
+		{
+			name:       "unfuzzable",
+			in:         []Option{{K: "age", V: "optional && gt(0)"}},
+			wantErrStr: "cannot fuzz age (only the params of the basic types and []byte are supported)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mt, err := buildMethodTests(b, method, map[string][]Option{"schema": tt.in}, nil)
+			if err != nil || mt == nil {
+				t.Fatalf("method skipped: %v", err)
+			}
+			if mt.fuzzErr != nil {
+				if mt.fuzzErr.Error() != tt.wantErrStr {
+					t.Fatalf("Err: got (%#v), want (%#v)", mt.fuzzErr.Error(), tt.wantErrStr)
+				}
+				return
+			}
+			if tt.wantErrStr != "" {
+				t.Fatalf("Err: got (%#v), want (%#v)", "", tt.wantErrStr)
+			}
+			if !cmp.Equal(mt.Fuzz, tt.want, cmpopts.IgnoreFields(ifacetool.Param{}, "Type")) {
+				diff := cmp.Diff(tt.want, mt.Fuzz, cmpopts.IgnoreFields(ifacetool.Param{}, "Type"))
+				t.Errorf("Want - Got: %s", diff)
+			}
+		})
+	}
+}
+
+func TestGenerator_GenerateTests(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping the generated tests in short mode")
+	}
+
+	// The generated files must be in this module to resolve its packages.
+	dir, err := os.MkdirTemp("testdata", "fixture")
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+	defer os.RemoveAll(dir)
+
+	src, err := os.ReadFile("testdata/fixture/service.go")
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+	filename, err := filepath.Abs(filepath.Join(dir, "service.go"))
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+	if err := os.WriteFile(filename, src, 0644); err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+
+	data := fixtureData(t, filename, "Service")
+	g := &Generator{
+		OutDir:      dir,
+		Formatted:   true,
+		Tests:       true,
+		Fuzz:        true,
+		SrcFilename: filename,
+	}
+
+	var files []*generator.File
+	warnings := captureStderr(t, func() {
+		file, err := g.Generate(data)
+		if err != nil {
+			t.Fatalf("err: %v\n", err)
+		}
+		testFile, err := g.GenerateTests(data)
+		if err != nil {
+			t.Fatalf("err: %v\n", err)
+		}
+		files = append(files, file, testFile)
+	})
+	for _, f := range files {
+		if err := f.Write(); err != nil {
+			t.Fatalf("err: %v\n", err)
+		}
+	}
+
+	wantWarnings := "warning: Rename: cannot test the params against @assert (skipped)\n" +
+		"warning: Tag: cannot fuzz tags (only the params of the basic types and []byte are supported) (fuzz target skipped)\n"
+	if warnings != wantWarnings {
+		t.Errorf("Warnings: got (%s), want (%s)", warnings, wantWarnings)
+	}
+
+	// The skips are also noted in the generated file.
+	for _, want := range []string{
+		"// Rename is not tested: cannot test the params against @assert.\n",
+		"// Tag is not fuzzed: cannot fuzz tags (only the params of the basic types and []byte are supported).\n",
+	} {
+		if !strings.Contains(string(files[1].Content), want) {
+			t.Errorf("Missing note %q in:\n%s", want, files[1].Content)
+		}
+	}
+
+	// Run the generated tests, along with the seed corpus of the fuzz targets.
+	cmd := exec.Command("go", "test", "-count=1", "-v", ".")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("err: %v\n%s", err, out)
+	}
+	for _, name := range []string{
+		"TestValidateMiddleware_Create",
+		"TestValidateMiddleware_Search",
+		"TestValidateMiddleware_Track",
+		"TestValidateMiddleware_Tag",
+		"FuzzValidateMiddleware_Create",
+		"FuzzValidateMiddleware_Search",
+		"FuzzValidateMiddleware_Track",
+	} {
+		if !strings.Contains(string(out), "--- PASS: "+name+" ") {
+			t.Errorf("%s did not pass:\n%s", name, out)
+		}
+	}
+}
+
+// fixtureData returns the data of the interface name, which is declared in
+// filename, as the protogo parser does.
+func fixtureData(t *testing.T, filename, name string) *ifacetool.Data {
+	pkg, file, err := loadFile(filename)
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+	iface, ok := pkg.Scope().Lookup(name).Type().Underlying().(*types.Interface)
+	if !ok {
+		t.Fatalf("%s is not an interface", name)
+	}
+
+	qualifier := func(p *types.Package) string {
+		if p == pkg {
+			return ""
+		}
+		return p.Name()
+	}
+	newParams := func(vars *types.Tuple, variadic bool) (params []*ifacetool.Param) {
+		for i := 0; i < vars.Len(); i++ {
+			v := vars.At(i)
+			params = append(params, &ifacetool.Param{
+				Name:       v.Name(),
+				TypeString: types.TypeString(v.Type(), qualifier),
+				Type:       v.Type(),
+				Variadic:   variadic && i == vars.Len()-1,
+			})
+		}
+		return
+	}
+
+	data := &ifacetool.Data{
+		PkgName:       pkg.Name(),
+		SrcPkgName:    pkg.Name(),
+		InterfaceName: name,
+		Imports:       []*ifacetool.Import{{Path: "context"}},
+	}
+	docs := methodDocs(file, name)
+	for i := 0; i < iface.NumMethods(); i++ {
+		m := iface.Method(i)
+		sig := m.Type().(*types.Signature)
+		method := &ifacetool.Method{
+			Name:    m.Name(),
+			Params:  newParams(sig.Params(), sig.Variadic()),
+			Returns: newParams(sig.Results(), false),
+		}
+		if doc := docs[m.Name()]; doc != nil {
+			for _, c := range doc.List {
+				method.Doc = append(method.Doc, c.Text)
+			}
+		}
+		data.Methods = append(data.Methods, method)
+	}
+	return data
+}
+
+// captureStderr returns what f writes to os.Stderr.
+func captureStderr(t *testing.T, f func()) string {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+	stderr := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = stderr }()
+
+	out := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		out <- string(b)
+	}()
+	f()
+	w.Close()
+	return <-out
+}
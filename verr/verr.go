@@ -0,0 +1,128 @@
+// Package verr provides structured validation errors, which carry the
+// information about the validators in addition to the field and message.
+package verr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	v "github.com/RussellLuo/validating/v3"
+)
+
+// Info holds the information about a leaf validator.
+type Info struct {
+	// Validator is the name (i.e. alias) of the validator, e.g. `len`.
+	Validator string
+	// Args are the arguments of the validator.
+	Args []string
+	// Code is the custom error code, which is specified by `.code(...)`.
+	Code string
+}
+
+// Detail is the machine-readable representation of an error.
+type Detail struct {
+	Field     string   `json:"field"`
+	Kind      string   `json:"kind"`
+	Validator string   `json:"validator,omitempty"`
+	Args      []string `json:"args,omitempty"`
+	Message   string   `json:"message"`
+	Code      string   `json:"code,omitempty"`
+}
+
+// Error is a structured validation error, which implements v.Error.
+type Error struct {
+	field   string
+	kind    string
+	message string
+	info    Info
+}
+
+// NewError creates a structured error from err, along with the information
+// about the validator that reported err.
+func NewError(err v.Error, info Info) *Error {
+	return &Error{
+		field:   err.Field(),
+		kind:    err.Kind(),
+		message: err.Message(),
+		info:    info,
+	}
+}
+
+func (e *Error) Field() string     { return e.field }
+func (e *Error) Kind() string      { return e.kind }
+func (e *Error) Message() string   { return e.message }
+func (e *Error) Validator() string { return e.info.Validator }
+func (e *Error) Args() []string    { return e.info.Args }
+func (e *Error) Code() string      { return e.info.Code }
+
+// Error returns the same string as the errors of validating, e.g.
+// `name: INVALID(has an invalid length)`.
+func (e *Error) Error() string {
+	s := fmt.Sprintf("%s(%s)", e.kind, e.message)
+	if e.field == "" {
+		return s
+	}
+	return fmt.Sprintf("%s: %s", e.field, s)
+}
+
+// Detail returns the machine-readable representation of e.
+func (e *Error) Detail() Detail {
+	return Detail{
+		Field:     e.field,
+		Kind:      e.kind,
+		Validator: e.info.Validator,
+		Args:      e.info.Args,
+		Message:   e.message,
+		Code:      e.info.Code,
+	}
+}
+
+func (e *Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.Detail())
+}
+
+// Leaf wraps the leaf validator, whose errors will be converted to
+// structured errors carrying info.
+func Leaf(info Info, validator v.Validator) v.Validator {
+	return v.Func(func(field *v.Field) v.Errors {
+		errs := validator.Validate(field)
+		if len(errs) == 0 {
+			return nil
+		}
+
+		newErrs := make(v.Errors, len(errs))
+		for i, err := range errs {
+			if _, ok := err.(*Error); ok {
+				newErrs[i] = err
+				continue
+			}
+			newErrs[i] = NewError(err, info)
+		}
+		return newErrs
+	})
+}
+
+// Details returns the machine-readable representations of the validation
+// errors in err, which is usually returned by the validation middleware.
+// It returns nil if err holds no validation errors.
+func Details(err error) []Detail {
+	var errs v.Errors
+	if !errors.As(err, &errs) {
+		return nil
+	}
+
+	details := make([]Detail, len(errs))
+	for i, e := range errs {
+		if se, ok := e.(*Error); ok {
+			details[i] = se.Detail()
+			continue
+		}
+		details[i] = Detail{
+			Field:   e.Field(),
+			Kind:    e.Kind(),
+			Message: e.Message(),
+		}
+	}
+	return details
+}
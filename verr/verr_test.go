@@ -0,0 +1,106 @@
+package verr_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	v "github.com/RussellLuo/validating/v3"
+	"github.com/google/go-cmp/cmp"
+	"github.com/protogodev/validate/verr"
+)
+
+func TestDetails(t *testing.T) {
+	tests := []struct {
+		name string
+		in   error
+		want []verr.Detail
+	}{
+		{
+			name: "leaf",
+			in: v.Validate(v.Value("ab", verr.Leaf(
+				verr.Info{Validator: "len", Args: []string{"3", "10"}, Code: "USR_001"},
+				v.LenString(3, 10),
+			))),
+			want: []verr.Detail{
+				{
+					Kind:      v.ErrInvalid,
+					Validator: "len",
+					Args:      []string{"3", "10"},
+					Message:   "has an invalid length",
+					Code:      "USR_001",
+				},
+			},
+		},
+		{
+			name: "nested leaf",
+			in: v.Validate(v.Schema{
+				v.F("name", ""): verr.Leaf(verr.Info{Validator: "nonzero"}, v.Nonzero[string]()),
+				v.F("age", 1):   verr.Leaf(verr.Info{Validator: "gt", Args: []string{"0"}}, v.Gt(0)),
+			}),
+			want: []verr.Detail{
+				{
+					Field:     "name",
+					Kind:      v.ErrInvalid,
+					Validator: "nonzero",
+					Message:   "is zero valued",
+				},
+			},
+		},
+		{
+			name: "plain",
+			in:   v.Validate(v.Value("", v.Nonzero[string]())),
+			want: []verr.Detail{
+				{
+					Kind:    v.ErrInvalid,
+					Message: "is zero valued",
+				},
+			},
+		},
+		{
+			name: "wrapped",
+			in:   fmt.Errorf("bad request: %w", v.Validate(v.Value("", v.Nonzero[string]()))),
+			want: []verr.Detail{
+				{
+					Kind:    v.ErrInvalid,
+					Message: "is zero valued",
+				},
+			},
+		},
+		{
+			name: "other",
+			in:   fmt.Errorf("oops"),
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := verr.Details(tt.in)
+			if !cmp.Equal(got, tt.want) {
+				diff := cmp.Diff(got, tt.want)
+				t.Errorf("Want - Got: %s", diff)
+			}
+		})
+	}
+}
+
+func TestError(t *testing.T) {
+	err := verr.NewError(
+		v.NewError("user.name", v.ErrInvalid, "has an invalid length"),
+		verr.Info{Validator: "len", Args: []string{"1", "10"}, Code: "USR_001"},
+	)
+
+	if got, want := err.Error(), "user.name: INVALID(has an invalid length)"; got != want {
+		t.Errorf("Error: got (%q), want (%q)", got, want)
+	}
+
+	b, jerr := json.Marshal(err)
+	if jerr != nil {
+		t.Fatalf("err: %v\n", jerr)
+	}
+	want := `{"field":"user.name","kind":"INVALID","validator":"len","args":["1","10"],"message":"has an invalid length","code":"USR_001"}`
+	if got := string(b); got != want {
+		t.Errorf("JSON: got (%s), want (%s)", got, want)
+	}
+}
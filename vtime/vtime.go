@@ -0,0 +1,92 @@
+// Package vtime provides the validators for time.Time, which are declared as
+// builtin validators (e.g. `after`, `within` and `future`).
+package vtime
+
+import (
+	"time"
+
+	v "github.com/RussellLuo/validating/v3"
+)
+
+// Now returns the current time, against which the time-relative validators
+// (i.e. Within, Future and Past) check the values. It can be replaced in
+// tests for deterministic results.
+var Now = time.Now
+
+// check creates a leaf validator, which will succeed when the field's value
+// is a time.Time satisfying valid.
+func check(name, message string, valid func(t time.Time) bool) (mv *v.MessageValidator) {
+	mv = &v.MessageValidator{
+		Message: message,
+		Validator: v.Func(func(field *v.Field) v.Errors {
+			t, ok := field.Value.(time.Time)
+			if !ok {
+				return v.NewUnsupportedErrors(field, name)
+			}
+
+			if !valid(t) {
+				return v.NewErrors(field.Name, v.ErrInvalid, mv.Message)
+			}
+			return nil
+		}),
+	}
+	return
+}
+
+// Nonzero is a leaf validator factory used to create a validator, which will
+// succeed when the field's value is not the zero time (see time.Time.IsZero).
+func Nonzero() *v.MessageValidator {
+	return check("Nonzero", "is zero valued", func(t time.Time) bool {
+		return !t.IsZero()
+	})
+}
+
+// Zero is a leaf validator factory used to create a validator, which will
+// succeed when the field's value is the zero time (see time.Time.IsZero).
+func Zero() *v.MessageValidator {
+	return check("Zero", "is nonzero", func(t time.Time) bool {
+		return t.IsZero()
+	})
+}
+
+// After is a leaf validator factory used to create a validator, which will
+// succeed when the field's value is after u.
+func After(u time.Time) *v.MessageValidator {
+	return check("After", "is not after the given time", func(t time.Time) bool {
+		return t.After(u)
+	})
+}
+
+// Before is a leaf validator factory used to create a validator, which will
+// succeed when the field's value is before u.
+func Before(u time.Time) *v.MessageValidator {
+	return check("Before", "is not before the given time", func(t time.Time) bool {
+		return t.Before(u)
+	})
+}
+
+// Within is a leaf validator factory used to create a validator, which will
+// succeed when the field's value is within d before or after the current
+// time.
+func Within(d time.Duration) *v.MessageValidator {
+	return check("Within", "is not within the given duration", func(t time.Time) bool {
+		diff := t.Sub(Now())
+		return -d <= diff && diff <= d
+	})
+}
+
+// Future is a leaf validator factory used to create a validator, which will
+// succeed when the field's value is after the current time.
+func Future() *v.MessageValidator {
+	return check("Future", "is not in the future", func(t time.Time) bool {
+		return t.After(Now())
+	})
+}
+
+// Past is a leaf validator factory used to create a validator, which will
+// succeed when the field's value is before the current time.
+func Past() *v.MessageValidator {
+	return check("Past", "is not in the past", func(t time.Time) bool {
+		return t.Before(Now())
+	})
+}
@@ -0,0 +1,49 @@
+package vtime_test
+
+import (
+	"testing"
+	"time"
+
+	v "github.com/RussellLuo/validating/v3"
+	"github.com/protogodev/validate/vtime"
+)
+
+func TestValidators(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	vtime.Now = func() time.Time { return now }
+	defer func() { vtime.Now = time.Now }()
+
+	tests := []struct {
+		name      string
+		validator v.Validator
+		in        interface{}
+		wantErr   string
+	}{
+		{"nonzero", vtime.Nonzero(), now, ""},
+		{"nonzero invalid", vtime.Nonzero(), time.Time{}.In(time.Local), "INVALID(is zero valued)"},
+		{"zero", vtime.Zero(), time.Time{}, ""},
+		{"after", vtime.After(now), now.Add(time.Second), ""},
+		{"after invalid", vtime.After(now), now, "INVALID(is not after the given time)"},
+		{"before", vtime.Before(now), now.Add(-time.Second), ""},
+		{"before invalid", vtime.Before(now), now, "INVALID(is not before the given time)"},
+		{"within", vtime.Within(time.Hour), now.Add(-time.Hour), ""},
+		{"within invalid", vtime.Within(time.Hour), now.Add(time.Hour + 1), "INVALID(is not within the given duration)"},
+		{"future", vtime.Future(), now.Add(1), ""},
+		{"future invalid", vtime.Future(), now, "INVALID(is not in the future)"},
+		{"past", vtime.Past(), now.Add(-1), ""},
+		{"past invalid", vtime.Past(), now, "INVALID(is not in the past)"},
+		{"unsupported", vtime.Past(), "2024-01-01", "UNSUPPORTED(cannot use validator `Past` on type string)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotErr string
+			if err := v.Validate(v.Value(tt.in, tt.validator)); err != nil {
+				gotErr = err.Error()
+			}
+			if gotErr != tt.wantErr {
+				t.Errorf("Err: got (%#v), want (%#v)", gotErr, tt.wantErr)
+			}
+		})
+	}
+}
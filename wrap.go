@@ -0,0 +1,242 @@
+package validate
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	v "github.com/RussellLuo/validating/v3"
+	"github.com/protogodev/validate/runtime"
+)
+
+// Rules are the validation rules used by Wrap.
+type Rules struct {
+	// Methods maps the method names to the rules of the methods.
+	Methods map[string]MethodRules
+	// Registry holds the validators, which defaults to the builtin ones
+	// (see runtime.New).
+	Registry *runtime.Registry
+	// Wrap wraps the validation errors, just like the argument of the
+	// generated ValidateMiddleware.
+	Wrap func(error) error
+}
+
+// MethodRules are the validation rules of a method.
+type MethodRules struct {
+	// Params are the names of all the method params in order, which are not
+	// available by reflection.
+	Params []string
+	// Schema holds the options of the `@schema` annotation (e.g.
+	// `ParseDoc(doc)["schema"]`), which validate the params.
+	Schema []Option
+}
+
+var (
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+)
+
+// Wrap validates the params of the methods of impl per rules at runtime,
+// which is an alternative to the generated ValidateMiddleware for
+// prototypes and tests.
+//
+// Since methods cannot be created by reflection, impl must be a struct (or
+// a pointer to a struct) holding the methods as function fields, which are
+// named either `Method` or `MethodFunc`. If T is an interface, impl must
+// hold such a struct, whose methods delegate to the function fields. The
+// returned value is a copy of impl, whose function fields are wrapped.
+func Wrap[T any](impl T, rules Rules) (T, error) {
+	if rules.Registry == nil {
+		rules.Registry = runtime.New()
+	}
+	if rules.Wrap == nil {
+		rules.Wrap = func(err error) error { return err }
+	}
+
+	value := reflect.ValueOf(&impl).Elem()
+	if value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
+
+	// Copy the struct, whose fields will be replaced.
+	var s reflect.Value
+	switch {
+	case value.Kind() == reflect.Struct:
+		s = reflect.New(value.Type()).Elem()
+		s.Set(value)
+	case value.Kind() == reflect.Pointer && !value.IsNil() && value.Elem().Kind() == reflect.Struct:
+		s = reflect.New(value.Type().Elem()).Elem()
+		s.Set(value.Elem())
+	default:
+		return impl, fmt.Errorf("cannot wrap %T (want a struct of function fields)", impl)
+	}
+
+	// Wrap the methods in order, which makes the errors deterministic.
+	names := make([]string, 0, len(rules.Methods))
+	for name := range rules.Methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		m := rules.Methods[name]
+		field, err := funcField(s, name)
+		if err != nil {
+			return impl, err
+		}
+		wrapped, err := wrapFunc(field, m, rules)
+		if err != nil {
+			return impl, fmt.Errorf("method %s: %w", name, err)
+		}
+		field.Set(wrapped)
+	}
+
+	result := s
+	if value.Kind() == reflect.Pointer {
+		result = s.Addr()
+	}
+	var wrapped T
+	reflect.ValueOf(&wrapped).Elem().Set(result)
+	return wrapped, nil
+}
+
+// funcField returns the function field of the struct s, which represents
+// the method name.
+func funcField(s reflect.Value, name string) (reflect.Value, error) {
+	for _, fieldName := range []string{name, name + "Func"} {
+		f, ok := s.Type().FieldByName(fieldName)
+		if !ok || f.Type.Kind() != reflect.Func || !f.IsExported() {
+			continue
+		}
+		return s.FieldByIndex(f.Index), nil
+	}
+	return reflect.Value{}, fmt.Errorf("cannot intercept method %s: %s has no function field %s or %sFunc", name, s.Type(), name, name)
+}
+
+// wrapField is a field to be validated, which is either a param or a nested
+// field of a param (e.g. `user.Name`).
+type wrapField struct {
+	name  string
+	param int
+	path  []string
+	rule  *runtime.Rule
+}
+
+// wrapFunc returns a function, which validates the params per m before
+// calling fn.
+func wrapFunc(fn reflect.Value, m MethodRules, rules Rules) (reflect.Value, error) {
+	// Detach fn from the field, which will be replaced.
+	fn = reflect.ValueOf(fn.Interface())
+
+	t := fn.Type()
+	if len(m.Params) != t.NumIn() {
+		return reflect.Value{}, fmt.Errorf("got %d param names, but the method has %d params", len(m.Params), t.NumIn())
+	}
+	if t.NumOut() == 0 || t.Out(t.NumOut()-1) != errorType {
+		return reflect.Value{}, fmt.Errorf("the last result must be an error")
+	}
+
+	scope := make(map[string]reflect.Type)
+	params := make(map[string]int)
+	for i, name := range m.Params {
+		scope[name] = t.In(i)
+		params[name] = i
+	}
+
+	var fields []*wrapField
+	for _, opt := range m.Schema {
+		path := strings.Split(opt.K, ".")
+		i, ok := params[path[0]]
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("no param %s", path[0])
+		}
+
+		typ := t.In(i)
+		for _, name := range path[1:] {
+			if typ.Kind() == reflect.Pointer {
+				typ = typ.Elem()
+			}
+			if typ.Kind() != reflect.Struct {
+				return reflect.Value{}, fmt.Errorf("%s: type %s is not a struct", opt.K, typ)
+			}
+			f, ok := typ.FieldByName(name)
+			if !ok || !f.IsExported() {
+				return reflect.Value{}, fmt.Errorf("%s: type %s has no exported field %s", opt.K, typ, name)
+			}
+			typ = f.Type
+		}
+
+		rule, err := rules.Registry.Compile(opt.V, typ, scope)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("%s: %w", opt.K, err)
+		}
+		fields = append(fields, &wrapField{name: opt.K, param: i, path: path[1:], rule: rule})
+	}
+
+	return reflect.MakeFunc(t, func(args []reflect.Value) []reflect.Value {
+		if err := validateArgs(args, m.Params, fields); err != nil {
+			results := make([]reflect.Value, t.NumOut())
+			for i := range results {
+				results[i] = reflect.Zero(t.Out(i))
+			}
+			if err := rules.Wrap(err); err != nil {
+				results[len(results)-1] = reflect.ValueOf(&err).Elem()
+			}
+			return results
+		}
+
+		if t.IsVariadic() {
+			return fn.CallSlice(args)
+		}
+		return fn.Call(args)
+	}), nil
+}
+
+// validateArgs validates the fields of the arguments args, which are the
+// values of params.
+func validateArgs(args []reflect.Value, params []string, fields []*wrapField) error {
+	ctx := context.Background()
+	env := make(map[string]interface{})
+	for i, arg := range args {
+		env[params[i]] = arg.Interface()
+		if arg.Type() == contextType && !arg.IsNil() {
+			ctx = arg.Interface().(context.Context)
+		}
+	}
+
+	schema := make(v.Schema)
+	for _, f := range fields {
+		value, ok := fieldValue(args[f.param], f.path)
+		if !ok {
+			// Skip the nested field of a nil pointer.
+			continue
+		}
+		validator, err := f.rule.Validator(ctx, env)
+		if err != nil {
+			return err
+		}
+		schema[v.F(f.name, value.Interface())] = validator
+	}
+
+	if errs := v.Validate(schema); errs != nil {
+		return errs
+	}
+	return nil
+}
+
+// fieldValue returns the nested field of value by path, which reports false
+// if any pointer along the path is nil.
+func fieldValue(value reflect.Value, path []string) (reflect.Value, bool) {
+	for _, name := range path {
+		if value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				return reflect.Value{}, false
+			}
+			value = value.Elem()
+		}
+		value = value.FieldByName(name)
+	}
+	return value, true
+}
@@ -0,0 +1,213 @@
+package validate_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/protogodev/validate"
+)
+
+type Address struct {
+	Zip string
+}
+
+type User struct {
+	Name    string
+	Address *Address
+}
+
+type Service interface {
+	Create(ctx context.Context, user User, tags ...string) (int, error)
+	Range(min, max int) error
+}
+
+// ServiceFuncs implements Service by function fields.
+type ServiceFuncs struct {
+	CreateFunc func(ctx context.Context, user User, tags ...string) (int, error)
+	RangeFunc  func(min, max int) error
+}
+
+func (f ServiceFuncs) Create(ctx context.Context, user User, tags ...string) (int, error) {
+	return f.CreateFunc(ctx, user, tags...)
+}
+
+func (f ServiceFuncs) Range(min, max int) error {
+	return f.RangeFunc(min, max)
+}
+
+func TestWrap(t *testing.T) {
+	var impl Service = ServiceFuncs{
+		CreateFunc: func(ctx context.Context, user User, tags ...string) (int, error) { return 1, nil },
+		RangeFunc:  func(min, max int) error { return nil },
+	}
+
+	svc, err := validate.Wrap(impl, validate.Rules{
+		Methods: map[string]validate.MethodRules{
+			"Create": {
+				Params: []string{"ctx", "user", "tags"},
+				Schema: validate.ParseDoc([]string{
+					"// @schema:",
+					"//   user.Name: len(1, 5)",
+					"//   user.Address.Zip: len(5, 5)",
+					"//   tags: each(nonzero)",
+				})["schema"],
+			},
+			"Range": {
+				Params: []string{"min", "max"},
+				Schema: []validate.Option{{K: "max", V: "gt(min)"}},
+			},
+		},
+		Wrap: func(err error) error { return fmt.Errorf("bad request: %w", err) },
+	})
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+
+	tests := []struct {
+		name    string
+		call    func() error
+		wantErr string
+	}{
+		{
+			name: "ok",
+			call: func() error {
+				_, err := svc.Create(context.Background(), User{Name: "bob"}, "a")
+				return err
+			},
+		},
+		{
+			name: "nested",
+			call: func() error {
+				_, err := svc.Create(context.Background(), User{Name: "bob", Address: &Address{Zip: "1"}})
+				return err
+			},
+			wantErr: "bad request: user.Address.Zip: INVALID(has an invalid length)",
+		},
+		{
+			name: "variadic",
+			call: func() error {
+				_, err := svc.Create(context.Background(), User{Name: "bob"}, "a", "")
+				return err
+			},
+			wantErr: "bad request: tags[1]: INVALID(is zero valued)",
+		},
+		{
+			name:    "reference",
+			call:    func() error { return svc.Range(1, 1) },
+			wantErr: "bad request: max: INVALID(must be greater than min)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotErr string
+			if err := tt.call(); err != nil {
+				gotErr = err.Error()
+			}
+			if gotErr != tt.wantErr {
+				t.Errorf("Err: got (%#v), want (%#v)", gotErr, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWrap_Struct(t *testing.T) {
+	type funcs struct {
+		Greet func(name string) (string, error)
+	}
+	impl := &funcs{Greet: func(name string) (string, error) { return "Hello " + name, nil }}
+
+	wrapped, err := validate.Wrap(impl, validate.Rules{
+		Methods: map[string]validate.MethodRules{
+			"Greet": {
+				Params: []string{"name"},
+				Schema: []validate.Option{{K: "name", V: "len(1, 5)"}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+
+	if got, err := wrapped.Greet("bob"); got != "Hello bob" || err != nil {
+		t.Errorf("got (%q, %v), want (%q, <nil>)", got, err, "Hello bob")
+	}
+	if got, err := wrapped.Greet(""); got != "" || err == nil {
+		t.Errorf("got (%q, %v), want an error", got, err)
+	}
+	if _, err := impl.Greet(""); err != nil {
+		t.Errorf("impl has been modified, got err: %v", err)
+	}
+}
+
+func TestWrap_Error(t *testing.T) {
+	type funcs struct {
+		Greet  func(name string) (string, error)
+		Notify func(name string)
+	}
+
+	tests := []struct {
+		name    string
+		inImpl  interface{}
+		inRules map[string]validate.MethodRules
+		wantErr string
+	}{
+		{
+			name:    "not struct",
+			inImpl:  func() {},
+			wantErr: "cannot wrap func() (want a struct of function fields)",
+		},
+		{
+			name:    "no field",
+			inImpl:  funcs{},
+			inRules: map[string]validate.MethodRules{"Hello": {}},
+			wantErr: "cannot intercept method Hello: validate_test.funcs has no function field Hello or HelloFunc",
+		},
+		{
+			name:    "param names",
+			inImpl:  funcs{},
+			inRules: map[string]validate.MethodRules{"Greet": {}},
+			wantErr: "method Greet: got 0 param names, but the method has 1 params",
+		},
+		{
+			name:    "no error",
+			inImpl:  funcs{},
+			inRules: map[string]validate.MethodRules{"Notify": {Params: []string{"name"}}},
+			wantErr: "method Notify: the last result must be an error",
+		},
+		{
+			name:   "bad schema",
+			inImpl: funcs{},
+			inRules: map[string]validate.MethodRules{"Greet": {
+				Params: []string{"name"},
+				Schema: []validate.Option{{K: "name", V: "gt(0)"}},
+			}},
+			wantErr: "method Greet: name: 1:4 cannot use 0 (type untyped int) as string in argument to validator `gt`",
+		},
+		{
+			name:   "multiple errors",
+			inImpl: funcs{},
+			inRules: map[string]validate.MethodRules{
+				"Notify": {Params: []string{"name"}},
+				"Hello":  {},
+				"Greet":  {},
+			},
+			// The first error in the order of the method names.
+			wantErr: "method Greet: got 0 param names, but the method has 1 params",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := validate.Wrap(tt.inImpl, validate.Rules{Methods: tt.inRules})
+			if err == nil {
+				err = errors.New("")
+			}
+			if err.Error() != tt.wantErr {
+				t.Errorf("Err: got (%#v), want (%#v)", err.Error(), tt.wantErr)
+			}
+		})
+	}
+}